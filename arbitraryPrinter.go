@@ -18,8 +18,11 @@ func NewArbitraryPrinter(writer io.Writer, successor Printer) *ArbitraryPrinter
 	}
 }
 
+// Print calls the Output() of target if it is Printable, otherwise passes
+// it to the successor. Output() is called on a copy of target whose secret
+// fields are masked.
 func (p *ArbitraryPrinter) Print(target interface{}) error {
-	if v, ok := target.(Printable); ok {
+	if v, ok := MaskSecrets(target).(Printable); ok {
 		err := v.Output(p.writer)
 		return err
 	}
@@ -0,0 +1,147 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Builder loads a configuration of type T. It is the type-safe
+// counterpart of ConfigurationService: the target is allocated by the
+// builder, so it is always a pointer to T, and the failures of all sources
+// are returned together by Build().
+type Builder[T any] struct {
+	target  *T
+	service *ConfigurationService
+	err     error
+}
+
+// New creates a Builder for a fresh T. T must be a struct type.
+func New[T any]() *Builder[T] {
+	target := new(T)
+
+	builder := &Builder[T]{
+		target:  target,
+		service: NewConfigurationService(target).CollectErrors(),
+	}
+	if t := reflect.TypeOf(target).Elem(); t.Kind() != reflect.Struct {
+		builder.err = fmt.Errorf("config: the type %v of Builder must be a struct", t)
+	}
+	return builder
+}
+
+func (b *Builder[T]) FromEnv(prefix string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadEnvironmentVariables(prefix)
+	})
+}
+
+func (b *Builder[T]) FromDotEnv() *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadDotEnv()
+	})
+}
+
+func (b *Builder[T]) FromDotEnvFile(filepath string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadDotEnvFile(filepath)
+	})
+}
+
+func (b *Builder[T]) FromArgs() *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadCommandArguments()
+	})
+}
+
+func (b *Builder[T]) FromJson(filepath string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadJsonFile(filepath)
+	})
+}
+
+func (b *Builder[T]) FromJsonBytes(buffer []byte) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadJsonBytes(buffer)
+	})
+}
+
+func (b *Builder[T]) FromYaml(filepath string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadYamlFile(filepath)
+	})
+}
+
+func (b *Builder[T]) FromYamlBytes(buffer []byte) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadYamlBytes(buffer)
+	})
+}
+
+func (b *Builder[T]) FromResource(baseDir string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadResource(baseDir)
+	})
+}
+
+func (b *Builder[T]) FromFile(fullpath string, unmarshal UnmarshalFunc) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadFile(fullpath, unmarshal)
+	})
+}
+
+func (b *Builder[T]) FromBytes(buffer []byte, unmarshal UnmarshalFunc) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadBytes(buffer, unmarshal)
+	})
+}
+
+// WithPriority sets the priority of the source loaded by the next From*
+// call like ConfigurationService.WithPriority().
+func (b *Builder[T]) WithPriority(priority Priority) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.WithPriority(priority)
+	})
+}
+
+// FromSource loads source like ConfigurationService.LoadSource().
+func (b *Builder[T]) FromSource(source Source) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadSource(source)
+	})
+}
+
+// From loads the source registered by name like
+// ConfigurationService.LoadFrom().
+func (b *Builder[T]) From(name string, location string) *Builder[T] {
+	return b.load(func(service *ConfigurationService) {
+		service.LoadFrom(name, location)
+	})
+}
+
+// Build finishes the loading like ConfigurationService.Build(), and
+// returns the loaded value, or nil and a *ConfigurationError listing every
+// failure.
+func (b *Builder[T]) Build() (*T, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+
+	err := b.service.Build()
+	if err != nil {
+		return nil, err
+	}
+	return b.target, nil
+}
+
+// Service returns the underlying ConfigurationService, e.g. for reloading
+// by Watch() or sharing by NewStore().
+func (b *Builder[T]) Service() *ConfigurationService {
+	return b.service
+}
+
+func (b *Builder[T]) load(fn func(service *ConfigurationService)) *Builder[T] {
+	if b.err == nil {
+		fn(b.service)
+	}
+	return b
+}
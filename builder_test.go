@@ -0,0 +1,60 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestBuilder(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_DB", "32")
+
+	conf, err := New[DummyConfig]().
+		FromYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: 3")).
+		FromEnv("").
+		Build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expectedRedisHost = "127.0.0.1:6379"
+	if conf.RedisHost != expectedRedisHost {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", expectedRedisHost, conf.RedisHost)
+	}
+	var expectedRedisDB = 32
+	if conf.RedisDB != expectedRedisDB {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", expectedRedisDB, conf.RedisDB)
+	}
+}
+
+func TestBuilder_WithError(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_DB", "abc")
+
+	conf, err := New[DummyConfig]().
+		FromYamlBytes([]byte("redisDB: [")).
+		FromEnv("").
+		Build()
+	if conf != nil {
+		t.Errorf("assert 'Builder.Build()':: expected '%v', got '%v'", nil, conf)
+	}
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'Builder.Build()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 2 {
+		t.Errorf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", 2, configurationError.Errors)
+	}
+}
+
+func TestBuilder_WithUnsupportedType(t *testing.T) {
+	conf, err := New[map[string]string]().
+		FromYamlBytes([]byte("redisDB: 3")).
+		Build()
+	if conf != nil {
+		t.Errorf("assert 'Builder.Build()':: expected '%v', got '%v'", nil, conf)
+	}
+	if err == nil {
+		t.Errorf("assert 'Builder.Build()':: expected error, got '%v'", err)
+	}
+}
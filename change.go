@@ -0,0 +1,116 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+const (
+	ReloadTagName = "reload"
+	ReloadStatic  = "static"
+)
+
+// A Change describes a field whose value differs between two loads.
+type Change struct {
+	Field    string
+	Previous interface{}
+	Current  interface{}
+}
+
+type changeHandler struct {
+	field string
+	fn    func(changes []Change)
+}
+
+// OnChange registers fn to receive the changes of the specified field made
+// by Reload(). If the field is a nested struct, the changes of its fields
+// are delivered together, e.g. "Redis" matches "Redis.Host" and
+// "Redis.Port". The empty field matches all changes. The fn is called only
+// if there is any matched change.
+func (service *ConfigurationService) OnChange(field string, fn func(changes []Change)) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.changeHandlers = append(service.changeHandlers, &changeHandler{
+		field: field,
+		fn:    fn,
+	})
+}
+
+func (h *changeHandler) match(changes []Change) []Change {
+	if len(h.field) == 0 {
+		return changes
+	}
+
+	var matched []Change
+	for _, change := range changes {
+		if change.Field == h.field || strings.HasPrefix(change.Field, h.field+".") {
+			matched = append(matched, change)
+		}
+	}
+	return matched
+}
+
+// OnRestartRequired registers fn to receive the changes of the fields
+// tagged with `reload:"static"`. Reload() doesn't apply such changes, the
+// fields keep the previous values until the process restarts. A change is
+// reported once, and again only if the value changes again.
+func (service *ConfigurationService) OnRestartRequired(fn func(changes []Change)) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.restartHandlers = append(service.restartHandlers, fn)
+}
+
+// diff compares the previous and current targets. The changes of the
+// static fields are reverted in current and returned separately.
+func diff(previous, current interface{}) (changes []Change, restartRequired []Change) {
+	reflectutil.CompareLeaves(reflect.ValueOf(previous), reflect.ValueOf(current),
+		func(path []reflect.StructField, x, y reflect.Value) {
+			change := Change{
+				Field:    reflectutil.PathName(path),
+				Previous: x.Interface(),
+				Current:  reflectutil.DeepCopy(y).Interface(),
+			}
+			if isStatic(path) {
+				y.Set(reflectutil.DeepCopy(x))
+				restartRequired = append(restartRequired, change)
+				return
+			}
+			changes = append(changes, change)
+		})
+	return changes, restartRequired
+}
+
+// unreported returns the changes of the static fields which differ from
+// the ones reported by the previous reloads. Since the static fields keep
+// the previous values, the same changes are found by every reload until
+// the process restarts.
+func (service *ConfigurationService) unreported(restartRequired []Change) []Change {
+	var (
+		changes []Change
+		pending = make(map[string]interface{}, len(restartRequired))
+	)
+	for _, change := range restartRequired {
+		pending[change.Field] = change.Current
+		if reported, ok := service.pendingRestart[change.Field]; ok && reflect.DeepEqual(reported, change.Current) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	service.pendingRestart = pending
+	return changes
+}
+
+// isStatic reports whether the field or any struct containing it is
+// tagged with `reload:"static"`.
+func isStatic(path []reflect.StructField) bool {
+	for _, field := range path {
+		if field.Tag.Get(ReloadTagName) == ReloadStatic {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package config
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+type changeRedisConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+}
+
+type changeConfig struct {
+	Redis     changeRedisConfig `yaml:"redis"`
+	Workspace string            `yaml:"workspace"`
+}
+
+func TestConfigurationService_OnChange(t *testing.T) {
+	filename := path.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filename, []byte("redis:\n  host: 127.0.0.1\n  port: 6379\nworkspace: demo"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := changeConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+
+	var (
+		redisChanges     []Change
+		workspaceChanges []Change
+		allChanges       []Change
+	)
+	service.OnChange("Redis", func(changes []Change) {
+		redisChanges = append(redisChanges, changes...)
+	})
+	service.OnChange("Workspace", func(changes []Change) {
+		workspaceChanges = append(workspaceChanges, changes...)
+	})
+	service.OnChange("", func(changes []Change) {
+		allChanges = append(allChanges, changes...)
+	})
+
+	err = os.WriteFile(filename, []byte("redis:\n  host: 127.0.0.3\n  port: 6380\nworkspace: demo"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRedisChanges := []Change{
+		{Field: "Redis.Host", Previous: "127.0.0.1", Current: "127.0.0.3"},
+		{Field: "Redis.Port", Previous: 6379, Current: 6380},
+	}
+	if !reflect.DeepEqual(expectedRedisChanges, redisChanges) {
+		t.Errorf("assert 'OnChange(%q)':: expected '%#+v', got '%#+v'", "Redis", expectedRedisChanges, redisChanges)
+	}
+	if len(workspaceChanges) != 0 {
+		t.Errorf("assert 'OnChange(%q)':: expected no change, got '%#+v'", "Workspace", workspaceChanges)
+	}
+	if !reflect.DeepEqual(expectedRedisChanges, allChanges) {
+		t.Errorf("assert 'OnChange(%q)':: expected '%#+v', got '%#+v'", "", expectedRedisChanges, allChanges)
+	}
+
+	// reload again without modification
+	redisChanges = nil
+	err = service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(redisChanges) != 0 {
+		t.Errorf("assert 'OnChange(%q)':: expected no change, got '%#+v'", "Redis", redisChanges)
+	}
+}
+
+func TestConfigurationService_OnRestartRequired(t *testing.T) {
+	filename := path.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filename, []byte("redisPoolSize: 10\nworkspace: demo"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := struct {
+		RedisPoolSize int    `yaml:"redisPoolSize"   reload:"static"`
+		Workspace     string `yaml:"workspace"`
+	}{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+
+	var (
+		restartRequired []Change
+		changes         []Change
+		reloaded        interface{}
+	)
+	service.OnRestartRequired(func(v []Change) {
+		restartRequired = v
+	})
+	service.OnChange("", func(v []Change) {
+		changes = v
+	})
+	service.Subscribe(func(target interface{}) {
+		reloaded = target
+	})
+
+	err = os.WriteFile(filename, []byte("redisPoolSize: 50\nworkspace: demo_prod"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedRestartRequired := []Change{{Field: "RedisPoolSize", Previous: 10, Current: 50}}
+	if !reflect.DeepEqual(expectedRestartRequired, restartRequired) {
+		t.Errorf("assert 'OnRestartRequired()':: expected '%#+v', got '%#+v'", expectedRestartRequired, restartRequired)
+	}
+	expectedChanges := []Change{{Field: "Workspace", Previous: "demo", Current: "demo_prod"}}
+	if !reflect.DeepEqual(expectedChanges, changes) {
+		t.Errorf("assert 'OnChange()':: expected '%#+v', got '%#+v'", expectedChanges, changes)
+	}
+	poolSize := reflect.ValueOf(reloaded).Elem().FieldByName("RedisPoolSize").Int()
+	if poolSize != 10 {
+		t.Errorf("assert 'RedisPoolSize':: expected '%v', got '%v'", 10, poolSize)
+	}
+}
+
+func TestConfigurationService_OnRestartRequired_ReportOnce(t *testing.T) {
+	filename := path.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filename, []byte("redisPoolSize: 10"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := struct {
+		RedisPoolSize int `yaml:"redisPoolSize"   reload:"static"`
+	}{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+
+	var restartRequired [][]Change
+	service.OnRestartRequired(func(v []Change) {
+		restartRequired = append(restartRequired, v)
+	})
+
+	for _, content := range []string{"redisPoolSize: 50", "redisPoolSize: 50", "redisPoolSize: 10", "redisPoolSize: 50", "redisPoolSize: 60"} {
+		err = os.WriteFile(filename, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = service.Reload()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	expected := [][]Change{
+		{{Field: "RedisPoolSize", Previous: 10, Current: 50}},
+		{{Field: "RedisPoolSize", Previous: 10, Current: 50}},
+		{{Field: "RedisPoolSize", Previous: 10, Current: 60}},
+	}
+	if !reflect.DeepEqual(expected, restartRequired) {
+		t.Errorf("assert 'OnRestartRequired()':: expected '%#+v', got '%#+v'", expected, restartRequired)
+	}
+}
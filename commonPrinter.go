@@ -17,7 +17,8 @@ func NewCommonPrinter(writer io.Writer) *CommonPrinter {
 	}
 }
 
+// Print prints v in the %+v format, masking its secret fields.
 func (p *CommonPrinter) Print(v interface{}) error {
-	fmt.Fprintf(p.writer, "%+v\n", v)
+	fmt.Fprintf(p.writer, "%+v\n", MaskSecrets(v))
 	return nil
 }
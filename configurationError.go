@@ -0,0 +1,80 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/Bofry/config/internal/common"
+)
+
+var _ error = new(ConfigurationError)
+
+// A ConfigurationError reports every failure collected from the
+// configuration sources.
+type ConfigurationError struct {
+	Errors []*FieldError
+}
+
+func (e *ConfigurationError) Error() string {
+	if len(e.Errors) == 1 {
+		return fmt.Sprintf("config: %v", e.Errors[0])
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "config: %d errors occurred:", len(e.Errors))
+	for _, err := range e.Errors {
+		fmt.Fprintf(&sb, "\n\t* %v", err)
+	}
+	return sb.String()
+}
+
+// Is reports whether any collected error matches target, so errors.Is()
+// works before Go 1.20, which doesn't walk Unwrap() []error.
+func (e *ConfigurationError) Is(target error) bool {
+	for _, err := range e.Errors {
+		if errors.Is(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// As finds the first collected error which matches target, so errors.As()
+// works before Go 1.20, which doesn't walk Unwrap() []error.
+func (e *ConfigurationError) As(target interface{}) bool {
+	for _, err := range e.Errors {
+		if errors.As(err, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the collected errors.
+func (e *ConfigurationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+func makeFieldErrors(source string, err error) []*FieldError {
+	var errs []*FieldError
+	switch v := err.(type) {
+	case common.FieldErrors:
+		errs = v
+	case *common.FieldError:
+		errs = []*FieldError{v}
+	default:
+		errs = []*FieldError{{Err: err}}
+	}
+
+	for _, e := range errs {
+		if len(e.Source) == 0 {
+			e.Source = source
+		}
+	}
+	return errs
+}
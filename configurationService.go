@@ -1,156 +1,404 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"reflect"
+	"sync"
 
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/defaults"
 	"github.com/Bofry/config/internal/env"
+	"github.com/Bofry/config/internal/expand"
 	"github.com/Bofry/config/internal/flag"
 	"github.com/Bofry/config/internal/json"
+	"github.com/Bofry/config/internal/reflectutil"
 	"github.com/Bofry/config/internal/resource"
+	"github.com/Bofry/config/internal/validate"
 	"github.com/Bofry/config/internal/yaml"
 	"github.com/Bofry/structproto"
 )
 
 type ConfigurationService struct {
 	target interface{}
+	base   reflect.Value
+
+	initialized   bool
+	collectErrors bool
+	steps         []*loadStep
+	errors        []*FieldError
+	origins       map[string][]*Origin
+
+	hooked     bool
+	hookErrors []*FieldError
+
+	disallowedSourceHandler func(err *FieldError)
+	missingEnvPolicy        MissingEnvPolicy
+	expansions              []*expansion
+
+	pendingPriority  *Priority
+	sourcePriorities bool
+	lastPriority     Priority
+
+	current         interface{}
+	subscribers     []func(target interface{})
+	changeHandlers  []*changeHandler
+	restartHandlers []func(changes []Change)
+	pendingRestart  map[string]interface{}
+
+	reloadErrorHandlers []func(err error)
+	lastReloadFailure   *ReloadFailure
+
+	mutex       sync.Mutex
+	reloadMutex sync.Mutex
+}
+
+type loadStep struct {
+	source   string
+	location string
+	files    []string
+	naming   keyNaming
+	tagName  string
+	priority Priority
+	ordered  bool
+	apply    func(target interface{}, record common.Recorder) error
+	watch    func(changed func()) (stop func())
+}
+
+// expansion records an ExpandEnv() call, so it can be applied again when
+// the loading chain is replayed.
+type expansion struct {
+	prefix string
+	policy MissingEnvPolicy
 }
 
 func NewConfigurationService(target interface{}) *ConfigurationService {
 	instance := ConfigurationService{
-		target: target,
+		target:  target,
+		origins: make(map[string][]*Origin),
 	}
 	return &instance
 }
 
-func (service *ConfigurationService) LoadEnvironmentVariables(prefix string) *ConfigurationService {
-	err := env.Process(prefix, service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %v", err))
-	}
+// CollectErrors switches the service to non-panicking mode. Failures of
+// the subsequent Load* calls are collected instead of raising a panic and
+// can be retrieved by Err() or Build(). In this mode, the required fields
+// are checked by Require() or Build() only, rather than by each Load*
+// call, so a source loaded later can satisfy them. It's the only way to
+// check the required fields across all sources.
+func (service *ConfigurationService) CollectErrors() *ConfigurationService {
+	service.collectErrors = true
 	return service
 }
 
-func (service *ConfigurationService) LoadDotEnv() *ConfigurationService {
-	err := env.LoadDotEnv(service.target)
-	if err != nil && os.IsExist(err) {
-		panic(fmt.Errorf("config: %v", err))
+// Err returns a *ConfigurationError listing every failure collected by
+// the Load* calls in non-panicking mode, or nil.
+func (service *ConfigurationService) Err() error {
+	if len(service.errors) == 0 {
+		return nil
+	}
+	return &ConfigurationError{
+		Errors: append([]*FieldError(nil), service.errors...),
 	}
-	return service
 }
 
-func (service *ConfigurationService) LoadDotEnvFile(filepath string) *ConfigurationService {
-	err := env.LoadDotEnvFile(filepath, service.target)
-	if err != nil && os.IsExist(err) {
-		panic(fmt.Errorf("config: %v", err))
+// Require checks the required fields against the merged result of all
+// loaded sources, and returns a *ConfigurationError naming every missing
+// field with the keys which could provide it, or nil. A field is required
+// if it has the required tag, e.g. `required:"true"`, or the required flag
+// in its env, arg, or resource tag. In panicking mode, each Load* call
+// has already panicked on the missing required fields of its source.
+func (service *ConfigurationService) Require() error {
+	service.initialize()
+
+	errs := service.checkRequired(reflect.ValueOf(service.target))
+	if len(errs) > 0 {
+		return &ConfigurationError{
+			Errors: errs,
+		}
 	}
-	return service
+	return nil
 }
 
-func (service *ConfigurationService) LoadCommandArguments() *ConfigurationService {
-	err := flag.Process(service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %v", err))
+// Build finishes the loading chain. It calls the Normalize() and
+// AfterLoad() hooks of the target, then returns a *ConfigurationError
+// listing the failures collected by the Load* calls and the hooks, the
+// missing required fields, and the validation violations, or nil. The
+// hooks are called once for the loaded values; calling Build() again
+// doesn't call them unless another source is loaded in the meantime.
+func (service *ConfigurationService) Build() error {
+	service.initialize()
+	service.runHooks()
+
+	errs := append([]*FieldError(nil), service.errors...)
+	errs = append(errs, service.hookErrors...)
+	for _, check := range []func() error{service.Require, service.Validate} {
+		if err, ok := check().(*ConfigurationError); ok {
+			errs = append(errs, err.Errors...)
+		}
 	}
-	return service
+	if len(errs) > 0 {
+		return &ConfigurationError{
+			Errors: errs,
+		}
+	}
+	return nil
 }
 
-func (service *ConfigurationService) LoadJsonFile(filepath string) *ConfigurationService {
-	err := json.LoadFile(filepath, service.target)
-	if err != nil && os.IsExist(err) {
-		panic(fmt.Errorf("config: %v", err))
+// Origin returns where the current value of the specified field came
+// from, or nil if none of the loaded sources changed it. Fields of nested
+// structs are separated by period, e.g. "Redis.Host".
+func (service *ConfigurationService) Origin(field string) *Origin {
+	history := service.origins[field]
+	if len(history) == 0 {
+		return nil
 	}
-	return service
+	return history[len(history)-1]
+}
+
+// History returns every value assigned to the specified field by the
+// loaded sources, in loading order.
+func (service *ConfigurationService) History(field string) []*Origin {
+	return append([]*Origin(nil), service.origins[field]...)
+}
+
+func (service *ConfigurationService) LoadEnvironmentVariables(prefix string) *ConfigurationService {
+	return service.load(&loadStep{
+		source: SourceEnv,
+		naming: envNaming(prefix),
+		apply: func(target interface{}, record common.Recorder) error {
+			return env.Process(prefix, target, record)
+		},
+	})
+}
+
+func (service *ConfigurationService) LoadDotEnv() *ConfigurationService {
+	// NOTE: the variables set by the step are kept, so the reloads can
+	// update them without overriding the variables set by others
+	written := make(map[string]string)
+	return service.load(&loadStep{
+		source:   SourceDotEnv,
+		location: ".env",
+		files:    []string{".env"},
+		naming:   envNaming(""),
+		apply: func(target interface{}, record common.Recorder) error {
+			return ignoreNotExist(env.LoadDotEnv(written, target, record))
+		},
+	})
+}
+
+func (service *ConfigurationService) LoadDotEnvFile(filepath string) *ConfigurationService {
+	written := make(map[string]string)
+	return service.load(&loadStep{
+		source:   SourceDotEnv,
+		location: expandPath(filepath),
+		files:    []string{expandPath(filepath)},
+		naming:   envNaming(""),
+		apply: func(target interface{}, record common.Recorder) error {
+			return ignoreNotExist(env.LoadDotEnvFile(filepath, written, target, record))
+		},
+	})
+}
+
+func (service *ConfigurationService) LoadCommandArguments() *ConfigurationService {
+	// NOTE: the command line flags can be registered only once, so the
+	// parsed values are kept for replaying the step
+	var values map[string]string
+	return service.load(&loadStep{
+		source: SourceArg,
+		naming: argNaming(),
+		apply: func(target interface{}, record common.Recorder) error {
+			if values != nil {
+				return flag.Bind(target, values, record)
+			}
+
+			var err error
+			values, err = flag.Parse(target, record)
+			return err
+		},
+	})
+}
+
+func (service *ConfigurationService) LoadJsonFile(filepath string) *ConfigurationService {
+	return service.load(&loadStep{
+		source:   SourceJson,
+		location: expandPath(filepath),
+		files:    []string{expandPath(filepath)},
+		naming:   jsonNaming(),
+		apply: func(target interface{}, record common.Recorder) error {
+			return ignoreNotExist(json.LoadFile(filepath, target, record))
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadJsonBytes(buffer []byte) *ConfigurationService {
-	err := json.LoadBytes(buffer, service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %v", err))
-	}
-	return service
+	return service.load(&loadStep{
+		source: SourceJson,
+		naming: jsonNaming(),
+		apply: func(target interface{}, record common.Recorder) error {
+			return json.LoadBytes(buffer, target, record)
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadYamlFile(filepath string) *ConfigurationService {
-	err := yaml.LoadFile(filepath, service.target)
-	if err != nil && os.IsExist(err) {
-		panic(fmt.Errorf("config: %#v", err))
-	}
-	return service
+	return service.load(&loadStep{
+		source:   SourceYaml,
+		location: expandPath(filepath),
+		files:    []string{expandPath(filepath)},
+		naming:   yamlNaming(),
+		apply: func(target interface{}, record common.Recorder) error {
+			return ignoreNotExist(yaml.LoadFile(filepath, target, record))
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadYamlBytes(buffer []byte) *ConfigurationService {
-	err := yaml.LoadBytes(buffer, service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %v", err))
-	}
-	return service
+	return service.load(&loadStep{
+		source: SourceYaml,
+		naming: yamlNaming(),
+		apply: func(target interface{}, record common.Recorder) error {
+			return yaml.LoadBytes(buffer, target, record)
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadResource(baseDir string) *ConfigurationService {
-	err := resource.Process(baseDir, service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %v", err))
-	}
-	return service
+	naming := resourceNaming(expandPath(baseDir))
+	return service.load(&loadStep{
+		source: SourceResource,
+		files:  collectKeys(service.target, naming),
+		naming: naming,
+		apply: func(target interface{}, record common.Recorder) error {
+			return resource.Process(baseDir, target, record)
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadFile(fullpath string, unmarshal UnmarshalFunc) *ConfigurationService {
-	path := os.ExpandEnv(fullpath)
-	buffer, err := os.ReadFile(path)
-	if err != nil && os.IsExist(err) {
-		panic(fmt.Errorf("config: %#v", err))
-	}
+	path := expandPath(fullpath)
+	return service.load(&loadStep{
+		source:   SourceFile,
+		location: path,
+		files:    []string{path},
+		apply: func(target interface{}, record common.Recorder) error {
+			path, err := expand.ExpandEnv(fullpath)
+			if err != nil {
+				return &FieldError{
+					Key: fullpath,
+					Err: err,
+				}
+			}
 
-	err = unmarshal(buffer, service.target)
-	if err != nil {
-		panic(fmt.Errorf("config: %#v", err))
-	}
-	return service
+			buffer, err := os.ReadFile(path)
+			if err != nil {
+				return ignoreNotExist(err)
+			}
+
+			err = unmarshal(buffer, target)
+			if err != nil {
+				return &FieldError{
+					Key: path,
+					Err: err,
+				}
+			}
+			return nil
+		},
+	})
 }
 
 func (service *ConfigurationService) LoadBytes(buffer []byte, unmarshal UnmarshalFunc) *ConfigurationService {
-	err := unmarshal(buffer, service.target)
+	return service.load(&loadStep{
+		source: SourceFile,
+		apply: func(target interface{}, record common.Recorder) error {
+			return unmarshal(buffer, target)
+		},
+	})
+}
+
+// Validate checks the fields of the target against their validate tags,
+// then calls the Validate() hooks of the target. It returns a
+// *ConfigurationError listing every violation, or nil.
+func (service *ConfigurationService) Validate() error {
+	var errs []*FieldError
+
+	err := validate.Process(service.target)
 	if err != nil {
-		panic(fmt.Errorf("config: %#v", err))
+		errs = append(errs, makeFieldErrors(validate.TagName, err)...)
+	}
+	errs = append(errs, service.validateHooks()...)
+
+	if len(errs) > 0 {
+		return &ConfigurationError{
+			Errors: errs,
+		}
 	}
+	return nil
+}
+
+// SetMissingEnvPolicy sets how ExpandEnv() expands the missing variables,
+// MissingEnvFail by default.
+func (service *ConfigurationService) SetMissingEnvPolicy(policy MissingEnvPolicy) *ConfigurationService {
+	service.missingEnvPolicy = policy
 	return service
 }
 
+// ExpandEnv replaces the environment variable references in the strings
+// of the target, e.g. "${HOST}", "${HOST:-localhost}", "${TLS:+https}", or
+// "${TOKEN:?token is required}". It walks through the nested structs,
+// pointers, interfaces, slices, arrays, and map values. The variable names
+// are prefixed with prefix and underscore. It returns a
+// *ConfigurationError listing every unresolved variable with the path of
+// the field which refers to it, e.g. "Redis.Hosts[0]", or nil. The
+// expansion is recorded with the current policy and applied again after
+// the loading chain when the chain is rebuilt or reloaded.
 func (service *ConfigurationService) ExpandEnv(prefix string) error {
+	e := &expansion{
+		prefix: prefix,
+		policy: service.missingEnvPolicy,
+	}
+	service.expansions = append(service.expansions, e)
+	service.hooked = false
+	return service.expandEnv(e)
+}
+
+func (service *ConfigurationService) expandEnv(e *expansion) error {
+	prefix := e.prefix
 	if len(prefix) > 0 {
 		prefix += "_"
 	}
+	lookup := func(name string) (string, bool) {
+		return os.LookupEnv(prefix + name)
+	}
 
-	err := service.Map(func(field structproto.FieldInfo, rv reflect.Value) error {
-		var err error
-		defer func() {
-			ex := recover()
-			if v, ok := ex.(error); ok {
-				err = v
-			} else {
-				err = fmt.Errorf("%+v", err)
-			}
-		}()
-
-		switch rv.Kind() {
-		case reflect.String:
-			if !rv.IsZero() {
-				val := os.Expand(rv.String(), func(s string) string {
-					name := prefix + s
-					v := os.Getenv(name)
-					if len(v) == 0 {
-						panic(fmt.Errorf("missing environment variable '%s'", name))
-					}
-					return v
+	var errs []*FieldError
+	reflectutil.ReplaceStrings(reflect.ValueOf(service.target), func(path string, s string) string {
+		if len(s) == 0 {
+			return s
+		}
+
+		val, err := expand.Expand(s, lookup, e.policy)
+		if err != nil {
+			for _, missing := range err.(expand.MissingVariableErrors) {
+				errs = append(errs, &FieldError{
+					Source: SourceEnv,
+					Key:    prefix + missing.Name,
+					Field:  path,
+					Value:  s,
+					Err:    missing,
 				})
-				rv.SetString(val)
 			}
+			return s
 		}
-		return err
+		return val
 	})
-	return err
+	if len(errs) > 0 {
+		return &ConfigurationError{
+			Errors: errs,
+		}
+	}
+	return nil
 }
 
 func (service *ConfigurationService) Map(mapper structproto.StructMapper) error {
@@ -175,3 +423,197 @@ func (service *ConfigurationService) OutputWithPrinter(printer Printer) {
 		panic(fmt.Errorf("config: %#v", err))
 	}
 }
+
+func (service *ConfigurationService) load(step *loadStep) *ConfigurationService {
+	if !step.ordered {
+		step.ordered = true
+		step.priority = service.nextPriority(step)
+		service.lastPriority = step.priority
+	}
+
+	service.initialize()
+	service.hooked = false
+
+	// NOTE: the step is inserted after the steps of lower or the same
+	// priority. If it is not the last one, the chain is applied again.
+	index := len(service.steps)
+	for index > 0 && service.steps[index-1].priority > step.priority {
+		index--
+	}
+	if index == len(service.steps) {
+		service.steps = append(service.steps, step)
+		service.apply(step)
+	} else {
+		steps := make([]*loadStep, 0, len(service.steps)+1)
+		steps = append(steps, service.steps[:index]...)
+		steps = append(steps, step)
+		steps = append(steps, service.steps[index:]...)
+		service.rebuild(steps)
+	}
+	return service
+}
+
+// rebuild applies steps to the initial value of the target again.
+func (service *ConfigurationService) rebuild(steps []*loadStep) {
+	reflect.ValueOf(service.target).Elem().Set(reflectutil.DeepCopy(service.base).Elem())
+	service.initialized = false
+	service.hooked = false
+	service.hookErrors = nil
+	service.errors = nil
+	service.origins = make(map[string][]*Origin)
+
+	service.initialize()
+	service.steps = steps
+	for _, step := range steps {
+		service.apply(step)
+	}
+	// NOTE: the failures were reported by ExpandEnv() already
+	for _, e := range service.expansions {
+		service.expandEnv(e)
+	}
+}
+
+func (service *ConfigurationService) initialize() {
+	// NOTE: the default values are applied before any other source
+	if !service.initialized {
+		service.initialized = true
+		service.base = reflectutil.DeepCopy(reflect.ValueOf(service.target))
+		service.beforeLoad()
+		service.apply(&loadStep{
+			source: SourceDefault,
+			apply: func(target interface{}, record common.Recorder) error {
+				return defaults.Process(target)
+			},
+		})
+	}
+}
+
+// apply runs step on a scratch copy of the target and commits the copy
+// only if the step neither panics nor fails, so the target is never
+// half-written. In non-panicking mode, a failing step is discarded as a
+// whole and its failure is collected. In panicking mode, a failing step
+// rolls back the whole loading chain, so the target keeps the value given
+// to NewConfigurationService, and the loading can be retried on the same
+// service.
+func (service *ConfigurationService) apply(step *loadStep) {
+	scratch := reflectutil.DeepCopy(reflect.ValueOf(service.target))
+
+	assigned := newAssignments()
+	ex, err := run(step, scratch.Interface(), assigned.record)
+	err = service.requireFields(step.source, err, scratch)
+	if !service.collectErrors && (err != nil || ex != nil) {
+		service.rollback()
+		if ex != nil {
+			panic(ex)
+		}
+		service.handleError(step.source, err)
+	}
+	if ex != nil {
+		service.handleError(step.source, fmt.Errorf("%v", ex))
+		return
+	}
+	if err != nil {
+		service.handleError(step.source, err)
+		return
+	}
+
+	violations := service.restrictSources(step, scratch)
+	if len(violations) > 0 && service.disallowedSourceHandler == nil && !service.collectErrors {
+		service.rollback()
+		panic(&ConfigurationError{
+			Errors: violations,
+		})
+	}
+
+	service.track(step, scratch, assigned)
+	reflect.ValueOf(service.target).Elem().Set(scratch.Elem())
+	for _, violation := range violations {
+		if service.disallowedSourceHandler != nil {
+			service.disallowedSourceHandler(violation)
+			continue
+		}
+		service.errors = append(service.errors, violation)
+	}
+}
+
+func (service *ConfigurationService) rollback() {
+	if service.base.IsValid() {
+		reflect.ValueOf(service.target).Elem().Set(reflectutil.DeepCopy(service.base).Elem())
+	}
+	service.initialized = false
+	service.hooked = false
+	service.hookErrors = nil
+	service.steps = nil
+	service.expansions = nil
+	service.errors = nil
+	service.origins = make(map[string][]*Origin)
+}
+
+// track records the origins of the fields changed by step, and of the
+// fields assigned by step with the same values.
+func (service *ConfigurationService) track(step *loadStep, current reflect.Value, assigned *assignments) {
+	changed := make(map[string]bool)
+	reflectutil.CompareLeaves(reflect.ValueOf(service.target), current,
+		func(path []reflect.StructField, previous, current reflect.Value) {
+			field := reflectutil.PathName(path)
+			changed[field] = true
+			service.addOrigin(step, path, previous, current)
+		})
+	reflectutil.WalkLeaves(current, func(path []reflect.StructField, rv reflect.Value) {
+		if changed[reflectutil.PathName(path)] || !assigned.contains(step, path) {
+			return
+		}
+		service.addOrigin(step, path, rv, rv)
+	})
+}
+
+func (service *ConfigurationService) addOrigin(step *loadStep, path []reflect.StructField, previous, current reflect.Value) {
+	origin := &Origin{
+		Source:   step.source,
+		Location: step.location,
+		Value:    reflectutil.DeepCopy(current).Interface(),
+		Previous: reflectutil.DeepCopy(previous).Interface(),
+	}
+	if step.naming != nil {
+		origin.Key = step.naming(path)
+	}
+
+	field := reflectutil.PathName(path)
+	service.origins[field] = append(service.origins[field], origin)
+}
+
+func (service *ConfigurationService) handleError(source string, err error) {
+	errs := makeFieldErrors(source, err)
+	if !service.collectErrors {
+		panic(&ConfigurationError{
+			Errors: errs,
+		})
+	}
+
+	service.errors = append(service.errors, errs...)
+}
+
+func run(step *loadStep, target interface{}, record common.Recorder) (ex interface{}, err error) {
+	defer func() {
+		ex = recover()
+	}()
+
+	return nil, step.apply(target, record)
+}
+
+// expandPath expands the environment variables in path for recording the
+// location of the source; the loading reports the failure of expansion.
+func expandPath(path string) string {
+	v, err := expand.ExpandEnv(path)
+	if err != nil {
+		return path
+	}
+	return v
+}
+
+func ignoreNotExist(err error) error {
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
@@ -1,10 +1,12 @@
 package config
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
 	"reflect"
 	"strings"
 	"testing"
@@ -236,3 +238,570 @@ func TestConfigurationService_ExpandEnv(t *testing.T) {
 		t.Errorf("assert 'DummyConfig':: expected '%#+v', got '%#+v'", expected, conf)
 	}
 }
+
+func TestConfigurationService_ExpandEnv_WithDefault(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("APP_REDIS_HOST", "demo-kubernetes")
+
+	conf := DummyConfig{
+		RedisHost:     "${REDIS_HOST:-127.0.0.1}:${REDIS_PORT:-6379}",
+		RedisPassword: "${REDIS_PASSWORD}",
+		Workspace:     "demo${ENVIRONMENT:+_}${ENVIRONMENT:-}",
+		Version:       "${VERSION:?version is required}",
+	}
+
+	err := NewConfigurationService(&conf).
+		ExpandEnv("APP")
+
+	var expectedRedisHost = "demo-kubernetes:6379"
+	if conf.RedisHost != expectedRedisHost {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", expectedRedisHost, conf.RedisHost)
+	}
+	var expectedWorkspace = "demo"
+	if conf.Workspace != expectedWorkspace {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", expectedWorkspace, conf.Workspace)
+	}
+
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.ExpandEnv()':: expected '%T', got '%T'", configurationError, err)
+	}
+	var expectedKeys = map[string]string{
+		"RedisPassword": "APP_REDIS_PASSWORD",
+		"Version":       "APP_VERSION",
+	}
+	if len(configurationError.Errors) != len(expectedKeys) {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v' errors, got '%v'", len(expectedKeys), configurationError)
+	}
+	for _, e := range configurationError.Errors {
+		if expectedKeys[e.Field] != e.Key {
+			t.Errorf("assert 'ConfigurationError.Errors[%s].Key':: expected '%v', got '%v'", e.Field, expectedKeys[e.Field], e.Key)
+		}
+		var missingEnvError *MissingEnvError
+		if !errors.As(e, &missingEnvError) {
+			t.Errorf("assert 'ConfigurationError.Errors[%s]':: expected '%T', got '%v'", e.Field, missingEnvError, e.Err)
+		}
+	}
+}
+
+func TestConfigurationService_ExpandEnv_WithMissingEnvPolicy(t *testing.T) {
+	os.Clearenv()
+
+	conf := DummyConfig{
+		RedisHost: "${REDIS_HOST}",
+		Workspace: "demo_${ENVIRONMENT}",
+	}
+
+	err := NewConfigurationService(&conf).
+		SetMissingEnvPolicy(MissingEnvKeep).
+		ExpandEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.RedisHost != "${REDIS_HOST}" {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", "${REDIS_HOST}", conf.RedisHost)
+	}
+
+	err = NewConfigurationService(&conf).
+		SetMissingEnvPolicy(MissingEnvEmpty).
+		ExpandEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if conf.Workspace != "demo_" {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", "demo_", conf.Workspace)
+	}
+}
+
+func TestConfigurationService_ExpandEnv_WithNestedValues(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("HOST", "127.0.0.1")
+	t.Setenv("TOKEN", "abcd")
+
+	type RedisConfig struct {
+		Host  string
+		Hosts []string
+	}
+	conf := struct {
+		Redis   RedisConfig
+		Backup  *RedisConfig
+		Headers map[string]string
+		Extra   interface{}
+		Ports   [2]string
+	}{
+		Redis: RedisConfig{
+			Host:  "${HOST}:6379",
+			Hosts: []string{"${HOST}:6379", "${REPLICA_HOST}:6379"},
+		},
+		Backup: &RedisConfig{
+			Host: "${BACKUP_HOST}",
+		},
+		Headers: map[string]string{
+			"X-Token": "${TOKEN}",
+		},
+		Extra: "${HOST}",
+		Ports: [2]string{"${PORT:-6379}", "6380"},
+	}
+
+	err := NewConfigurationService(&conf).
+		ExpandEnv("")
+
+	var expectedHosts = []string{"127.0.0.1:6379", "${REPLICA_HOST}:6379"}
+	if conf.Redis.Host != "127.0.0.1:6379" {
+		t.Errorf("assert 'Redis.Host':: expected '%v', got '%v'", "127.0.0.1:6379", conf.Redis.Host)
+	}
+	if !reflect.DeepEqual(expectedHosts, conf.Redis.Hosts) {
+		t.Errorf("assert 'Redis.Hosts':: expected '%v', got '%v'", expectedHosts, conf.Redis.Hosts)
+	}
+	if conf.Headers["X-Token"] != "abcd" {
+		t.Errorf("assert 'Headers[X-Token]':: expected '%v', got '%v'", "abcd", conf.Headers["X-Token"])
+	}
+	if conf.Extra != "127.0.0.1" {
+		t.Errorf("assert 'Extra':: expected '%v', got '%v'", "127.0.0.1", conf.Extra)
+	}
+	if conf.Ports[0] != "6379" {
+		t.Errorf("assert 'Ports[0]':: expected '%v', got '%v'", "6379", conf.Ports[0])
+	}
+
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.ExpandEnv()':: expected '%T', got '%T'", configurationError, err)
+	}
+	var expectedFields = []string{"Redis.Hosts[1]", "Backup.Host"}
+	if len(configurationError.Errors) != len(expectedFields) {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v' errors, got '%v'", len(expectedFields), configurationError)
+	}
+	for i, expectedField := range expectedFields {
+		if configurationError.Errors[i].Field != expectedField {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Field':: expected '%v', got '%v'", i, expectedField, configurationError.Errors[i].Field)
+		}
+	}
+}
+
+func TestConfigurationService_LoadYamlFile_WithDefaultPath(t *testing.T) {
+	os.Clearenv()
+
+	dir := t.TempDir()
+	t.Setenv("CONFIG_DIR", dir)
+	err := os.WriteFile(filepath.Join(dir, "config.dev.yaml"), []byte("workspace: demo_dev"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := DummyConfig{}
+
+	err = NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlFile("${CONFIG_DIR}/config.${ENVIRONMENT:-dev}.yaml").
+		LoadJsonFile("${CONFIG_DIR}/config.${ENVIRONMENT:?environment is required}.json").
+		Err()
+
+	var expectedWorkspace = "demo_dev"
+	if conf.Workspace != expectedWorkspace {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", expectedWorkspace, conf.Workspace)
+	}
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 1 || configurationError.Errors[0].Source != SourceJson {
+		t.Errorf("assert 'ConfigurationError.Errors':: expected the error of source '%v', got '%v'", SourceJson, configurationError.Errors)
+	}
+}
+
+func TestConfigurationService_CollectErrors(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_DB", "abc")
+	t.Setenv("TAG", "demo")
+
+	conf := DummyConfig{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlBytes([]byte("redisDB: 3")).
+		LoadYamlBytes([]byte("redisDB: [")).
+		LoadEnvironmentVariables("").
+		Err()
+
+	if err == nil {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected error, got '%v'", err)
+	}
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 2 {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", 2, configurationError.Errors)
+	}
+	var expectedSources = []string{SourceYaml, SourceEnv}
+	for i, expectedSource := range expectedSources {
+		if configurationError.Errors[i].Source != expectedSource {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Source':: expected '%v', got '%v'", i, expectedSource, configurationError.Errors[i].Source)
+		}
+	}
+	var expectedField = "RedisDB"
+	if configurationError.Errors[1].Field != expectedField {
+		t.Errorf("assert 'ConfigurationError.Errors[1].Field':: expected '%v', got '%v'", expectedField, configurationError.Errors[1].Field)
+	}
+	var expectedValue = "abc"
+	if configurationError.Errors[1].Value != expectedValue {
+		t.Errorf("assert 'ConfigurationError.Errors[1].Value':: expected '%v', got '%v'", expectedValue, configurationError.Errors[1].Value)
+	}
+	var expected = DummyConfig{
+		RedisDB: 3,
+	}
+	if !reflect.DeepEqual(expected, conf) {
+		t.Errorf("assert 'DummyConfig':: expected '%#+v', got '%#+v'", expected, conf)
+	}
+}
+
+func TestConfigurationService_CollectErrors_DiscardFailingSource(t *testing.T) {
+	conf := DummyConfig{
+		Workspace: "demo",
+	}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlBytes([]byte("workspace: half\nredisDB: abc")).
+		Err()
+
+	if err == nil {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected error, got '%v'", err)
+	}
+	var expected = DummyConfig{
+		Workspace: "demo",
+	}
+	if !reflect.DeepEqual(expected, conf) {
+		t.Errorf("assert 'DummyConfig':: expected '%#+v', got '%#+v'", expected, conf)
+	}
+}
+
+func TestConfigurationService_CollectErrors_WithYamlTypeError(t *testing.T) {
+	conf := DummyConfig{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: abcdefghijklmn\nredisPoolSize: [10]")).
+		Err()
+
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 2 {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", 2, configurationError.Errors)
+	}
+	var expectedFields = []string{"RedisDB", "RedisPoolSize"}
+	var expectedKeys = []string{"redisDB", "redisPoolSize"}
+	var expectedValues = []interface{}{"abcdefghijklmn", nil}
+	for i, e := range configurationError.Errors {
+		if e.Field != expectedFields[i] {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Field':: expected '%v', got '%v'", i, expectedFields[i], e.Field)
+		}
+		if e.Key != expectedKeys[i] {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Key':: expected '%v', got '%v'", i, expectedKeys[i], e.Key)
+		}
+		if e.Value != expectedValues[i] {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Value':: expected '%v', got '%v'", i, expectedValues[i], e.Value)
+		}
+	}
+	if !strings.Contains(configurationError.Errors[0].Err.Error(), "line 2") {
+		t.Errorf("assert 'ConfigurationError.Errors[0].Err':: expected '%v', got '%v'", "line 2", configurationError.Errors[0].Err)
+	}
+}
+
+func TestConfigurationService_CollectErrors_WithJsonTypeError(t *testing.T) {
+	conf := struct {
+		Redis struct {
+			DB int `json:"db"`
+		} `json:"redis"`
+	}{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadJsonBytes([]byte(`{"redis": {"db": "abc"}}`)).
+		Err()
+
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 1 {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", 1, configurationError.Errors)
+	}
+	if e := configurationError.Errors[0]; e.Field != "Redis.DB" || e.Key != "redis.db" {
+		t.Errorf("assert 'ConfigurationError.Errors[0]':: expected field '%v' and key '%v', got '%v' and '%v'", "Redis.DB", "redis.db", e.Field, e.Key)
+	}
+}
+
+func TestConfigurationService_WithPanic(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err == nil {
+			t.Errorf("assert 'recover()':: expected error, got '%v'", err)
+		}
+	}()
+
+	conf := DummyConfig{}
+
+	NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisDB: ["))
+}
+
+func TestConfigurationService_WithPanic_Rollback(t *testing.T) {
+	conf := DummyConfig{
+		RedisHost: "127.0.0.1:6379",
+	}
+
+	service := NewConfigurationService(&conf)
+	func() {
+		defer func() {
+			err := recover()
+			if err == nil {
+				t.Errorf("assert 'recover()':: expected error, got '%v'", err)
+			}
+		}()
+
+		service.
+			LoadYamlBytes([]byte("redisDB: 3")).
+			LoadYamlBytes([]byte("redisPoolSize: 10")).
+			LoadBytes([]byte("workspace: demo"), func(buffer []byte, v interface{}) error {
+				v.(*DummyConfig).Workspace = "demo"
+				panic("unexpected")
+			})
+	}()
+
+	var expected = DummyConfig{
+		RedisHost: "127.0.0.1:6379",
+	}
+	if !reflect.DeepEqual(expected, conf) {
+		t.Errorf("assert 'DummyConfig':: expected '%#+v', got '%#+v'", expected, conf)
+	}
+
+	service.LoadYamlBytes([]byte("redisDB: 3"))
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+	if len(service.History("RedisDB")) != 1 {
+		t.Errorf("assert 'ConfigurationService.History()':: expected '%v', got '%v'", 1, service.History("RedisDB"))
+	}
+}
+
+func TestConfigurationService_WithPanic_RequiredFromPreviousSource(t *testing.T) {
+	os.Clearenv()
+
+	conf := struct {
+		RedisHost string `env:"*REDIS_HOST"  yaml:"redisHost"`
+		RedisDB   int    `env:"*REDIS_DB"    yaml:"redisDB"`
+	}{}
+
+	NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: 3")).
+		LoadEnvironmentVariables("")
+	if conf.RedisHost != "127.0.0.1:6379" {
+		t.Errorf("assert 'RedisHost':: expected '%v', got '%v'", "127.0.0.1:6379", conf.RedisHost)
+	}
+
+	defer func() {
+		err := recover()
+		configurationError, ok := err.(*ConfigurationError)
+		if !ok {
+			t.Fatalf("assert 'recover()':: expected '%T', got '%#+v'", configurationError, err)
+		}
+		if len(configurationError.Errors) != 1 || configurationError.Errors[0].Field != "RedisHost" {
+			t.Errorf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", "RedisHost", configurationError.Errors)
+		}
+	}()
+
+	conf.RedisHost, conf.RedisDB = "", 0
+	NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisDB: 3")).
+		LoadEnvironmentVariables("")
+}
+
+func TestConfigurationService_CollectErrors_WithPanic(t *testing.T) {
+	conf := DummyConfig{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlBytes([]byte("redisDB: 3")).
+		LoadBytes([]byte("workspace: demo"), func(buffer []byte, v interface{}) error {
+			v.(*DummyConfig).Workspace = "demo"
+			panic("unexpected")
+		}).
+		Err()
+
+	if err == nil {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected error, got '%v'", err)
+	}
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+	if conf.Workspace != "" {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", "", conf.Workspace)
+	}
+}
+
+func TestConfigurationService_Origin(t *testing.T) {
+	os.Clearenv()
+	initializeEnvironment()
+	initializekubernetesEnvironment()
+	initializeArgs()
+	initializeDotEnv()
+	initializeDotVERSION()
+	initializeConfigYaml()
+	initializeConfigProductionYaml()
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadDotEnv().
+		LoadEnvironmentVariables("").
+		LoadEnvironmentVariables("K8S").
+		LoadYamlFile("config.yaml").
+		LoadYamlFile("config.${ENVIRONMENT}.yaml").
+		LoadCommandArguments().
+		LoadResource("")
+
+	expected := map[string]*Origin{
+		"RedisHost":     {Source: SourceEnv, Key: "K8S_REDIS_HOST", Value: "demo-kubernetes:6379", Previous: "127.0.0.3:6379"},
+		"RedisDB":       {Source: SourceArg, Key: "redis-db", Value: 32, Previous: 12},
+		"RedisPoolSize": {Source: SourceYaml, Location: "config.production.yaml", Key: "redisPoolSize", Value: 50, Previous: 10},
+		"Tags":          {Source: SourceEnv, Key: "TAG", Value: []string{"demo", "test"}, Previous: []string{"demo", "test"}},
+		"Version":       {Source: SourceResource, Key: ".VERSION", Value: "v1.0.2", Previous: ""},
+	}
+	for field, expectedOrigin := range expected {
+		origin := service.Origin(field)
+		if !reflect.DeepEqual(expectedOrigin, origin) {
+			t.Errorf("assert 'ConfigurationService.Origin(%q)':: expected '%#+v', got '%#+v'", field, expectedOrigin, origin)
+		}
+	}
+	if origin := service.Origin("Unknown"); origin != nil {
+		t.Errorf("assert 'ConfigurationService.Origin(%q)':: expected '%v', got '%#+v'", "Unknown", nil, origin)
+	}
+}
+
+func TestConfigurationService_Origin_WithSameValue(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_HOST", "127.0.0.1:6379")
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: 3")).
+		LoadEnvironmentVariables("")
+
+	expected := &Origin{Source: SourceEnv, Key: "REDIS_HOST", Value: "127.0.0.1:6379", Previous: "127.0.0.1:6379"}
+	if origin := service.Origin("RedisHost"); !reflect.DeepEqual(expected, origin) {
+		t.Errorf("assert 'ConfigurationService.Origin(%q)':: expected '%#+v', got '%#+v'", "RedisHost", expected, origin)
+	}
+	if history := service.History("RedisHost"); len(history) != 2 || history[0].Source != SourceYaml {
+		t.Errorf("assert 'ConfigurationService.History(%q)':: expected '%v' origins, got '%v'", "RedisHost", 2, history)
+	}
+	expected = &Origin{Source: SourceYaml, Key: "redisDB", Value: 3, Previous: 0}
+	if origin := service.Origin("RedisDB"); !reflect.DeepEqual(expected, origin) {
+		t.Errorf("assert 'ConfigurationService.Origin(%q)':: expected '%#+v', got '%#+v'", "RedisDB", expected, origin)
+	}
+}
+
+func TestConfigurationService_WithDefaultTag(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_HOST", "127.0.0.3:6379")
+
+	conf := struct {
+		RedisHost     string   `env:"REDIS_HOST"       default:"127.0.0.1:6379"`
+		RedisDB       int      `env:"REDIS_DB"         default:"3"`
+		RedisPoolSize int      `yaml:"redisPoolSize"   default:"10"`
+		Tags          []string `env:"TAG"              default:"demo,test"`
+	}{
+		RedisPoolSize: 50,
+	}
+
+	service := NewConfigurationService(&conf).
+		LoadEnvironmentVariables("")
+
+	if conf.RedisHost != "127.0.0.3:6379" {
+		t.Errorf("assert 'RedisHost':: expected '%v', got '%v'", "127.0.0.3:6379", conf.RedisHost)
+	}
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+	if conf.RedisPoolSize != 50 {
+		t.Errorf("assert 'RedisPoolSize':: expected '%v', got '%v'", 50, conf.RedisPoolSize)
+	}
+	var expectedTags = []string{"demo", "test"}
+	if !reflect.DeepEqual(expectedTags, conf.Tags) {
+		t.Errorf("assert 'Tags':: expected '%v', got '%v'", expectedTags, conf.Tags)
+	}
+	if origin := service.Origin("RedisDB"); origin == nil || origin.Source != SourceDefault {
+		t.Errorf("assert 'ConfigurationService.Origin(%q)':: expected source '%v', got '%#+v'", "RedisDB", SourceDefault, origin)
+	}
+	if history := service.History("RedisHost"); len(history) != 2 {
+		t.Errorf("assert 'ConfigurationService.History(%q)':: expected '%v' entries, got '%#+v'", "RedisHost", 2, history)
+	}
+}
+
+func TestConfigurationService_Validate(t *testing.T) {
+	conf := struct {
+		RedisHost string `yaml:"redisHost"   validate:"hostport"`
+		RedisDB   int    `yaml:"redisDB"     validate:"min=0,max=15"`
+		Workspace string `yaml:"workspace"   validate:"nonzero"`
+	}{}
+
+	err := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1\nredisDB: 32")).
+		Validate()
+
+	if err == nil {
+		t.Fatalf("assert 'ConfigurationService.Validate()':: expected error, got '%v'", err)
+	}
+	configurationError := err.(*ConfigurationError)
+	var expectedFields = []string{"RedisHost", "RedisDB", "Workspace"}
+	if len(configurationError.Errors) != len(expectedFields) {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v' errors, got '%v'", len(expectedFields), configurationError)
+	}
+	for i, expectedField := range expectedFields {
+		if configurationError.Errors[i].Field != expectedField {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Field':: expected '%v', got '%v'", i, expectedField, configurationError.Errors[i].Field)
+		}
+	}
+}
+
+func TestConfigurationService_Build(t *testing.T) {
+	os.Clearenv()
+
+	conf := struct {
+		RedisHost     string `env:"*REDIS_HOST"       yaml:"redisHost"`
+		RedisPassword string `env:"*REDIS_PASSWORD"   yaml:"-"`
+		RedisDB       int    `env:"REDIS_DB"          yaml:"redisDB"       validate:"max=15"`
+		Workspace     string `env:"-"                 yaml:"workspace"     required:"true"`
+	}{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadEnvironmentVariables("").
+		LoadEnvironmentVariables("K8S").
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: 32")).
+		Build()
+
+	if err == nil {
+		t.Fatalf("assert 'ConfigurationService.Build()':: expected error, got '%v'", err)
+	}
+	configurationError := err.(*ConfigurationError)
+	var expectedFields = []string{"RedisPassword", "Workspace", "RedisDB"}
+	if len(configurationError.Errors) != len(expectedFields) {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v' errors, got '%v'", len(expectedFields), configurationError)
+	}
+	for i, expectedField := range expectedFields {
+		if configurationError.Errors[i].Field != expectedField {
+			t.Errorf("assert 'ConfigurationError.Errors[%d].Field':: expected '%v', got '%v'", i, expectedField, configurationError.Errors[i].Field)
+		}
+	}
+	if !errors.Is(configurationError.Errors[0], ErrMissingRequiredValue) {
+		t.Errorf("assert 'ConfigurationError.Errors[0]':: expected '%v', got '%v'", ErrMissingRequiredValue, configurationError.Errors[0])
+	}
+	var expectedMessage = "required: field 'RedisPassword': missing required value, expected from env REDIS_PASSWORD, env K8S_REDIS_PASSWORD"
+	if configurationError.Errors[0].Error() != expectedMessage {
+		t.Errorf("assert 'ConfigurationError.Errors[0].Error()':: expected '%v', got '%v'", expectedMessage, configurationError.Errors[0].Error())
+	}
+}
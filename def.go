@@ -3,6 +3,20 @@ package config
 import (
 	"io"
 	"os"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/expand"
+)
+
+const (
+	SourceDefault  = "default"
+	SourceEnv      = "env"
+	SourceDotEnv   = "dotenv"
+	SourceArg      = "arg"
+	SourceJson     = "json"
+	SourceYaml     = "yaml"
+	SourceResource = "resource"
+	SourceFile     = "file"
 )
 
 type (
@@ -13,10 +27,48 @@ type (
 	Printable interface {
 		Output(writer io.Writer) error
 	}
+
+	// BeforeLoader is called before the first source is loaded.
+	BeforeLoader interface {
+		BeforeLoad()
+	}
+
+	// Normalizer is called by Build() to normalize the loaded values.
+	Normalizer interface {
+		Normalize()
+	}
+
+	// AfterLoader is called by Build() after Normalize(), e.g. to derive
+	// values from the loaded ones.
+	AfterLoader interface {
+		AfterLoad() error
+	}
+
+	// Validator is called by Validate() and Build() after the validate
+	// tags are checked.
+	Validator interface {
+		Validate() error
+	}
 )
 
 type (
 	UnmarshalFunc func(buffer []byte, target interface{}) error
+
+	FieldError = common.FieldError
+
+	// MissingEnvPolicy decides how ExpandEnv() expands a missing
+	// environment variable, i.e. unset or empty.
+	MissingEnvPolicy = expand.Policy
+
+	// MissingEnvError reports the missing environment variables of
+	// ExpandEnv() and the file paths.
+	MissingEnvError = expand.MissingVariableError
+)
+
+const (
+	MissingEnvFail  = expand.PolicyError // fail with a *MissingEnvError
+	MissingEnvEmpty = expand.PolicyEmpty // expand to the empty string
+	MissingEnvKeep  = expand.PolicyKeep  // keep the reference, e.g. "${HOST}"
 )
 
 var (
@@ -3,13 +3,13 @@ package env
 import "github.com/Bofry/config/internal/env"
 
 func Process(prefix string, target interface{}) error {
-	return env.Process(prefix, target)
+	return env.Process(prefix, target, nil)
 }
 
 func LoadDotEnv(target interface{}) error {
-	return env.LoadDotEnv(target)
+	return env.LoadDotEnv(nil, target, nil)
 }
 
 func LoadDotEnvFile(filepath string, target interface{}) error {
-	return env.LoadDotEnvFile(filepath, target)
+	return env.LoadDotEnvFile(filepath, nil, target, nil)
 }
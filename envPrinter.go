@@ -0,0 +1,137 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+var _ Printer = new(EnvPrinter)
+
+var (
+	typeOfTime = reflect.TypeOf(time.Time{})
+
+	plainEnvValuePattern = regexp.MustCompile(`^[A-Za-z0-9_@%+=:,./-]+$`)
+)
+
+// EnvPrinter prints the fields which have env tags as KEY=value lines,
+// which can be loaded by LoadDotEnvFile() or sourced by a shell. The
+// values are quoted if necessary, and the slices are joined with comma.
+// The secret fields are printed as comments with masked values.
+type EnvPrinter struct {
+	writer io.Writer
+	prefix string
+	export bool
+}
+
+func NewEnvPrinter(writer io.Writer) *EnvPrinter {
+	return &EnvPrinter{
+		writer: writer,
+	}
+}
+
+// SetPrefix sets the prefix of the variable names, like the prefix given
+// to LoadEnvironmentVariables().
+func (p *EnvPrinter) SetPrefix(prefix string) *EnvPrinter {
+	p.prefix = prefix
+	return p
+}
+
+// SetExport makes the printer begin each line with "export ".
+func (p *EnvPrinter) SetExport(export bool) *EnvPrinter {
+	p.export = export
+	return p
+}
+
+func (p *EnvPrinter) Print(target interface{}) error {
+	var (
+		sb     strings.Builder
+		naming = envNaming(p.prefix)
+	)
+	reflectutil.WalkLeaves(reflect.ValueOf(target), func(path []reflect.StructField, rv reflect.Value) {
+		name := naming(path)
+		if len(name) == 0 {
+			return
+		}
+
+		if isSecret(path) {
+			sb.WriteString("# ")
+		}
+		if p.export {
+			sb.WriteString("export ")
+		}
+		sb.WriteString(name)
+		sb.WriteString("=")
+		if isSecret(path) {
+			sb.WriteString(SecretMask)
+		} else {
+			sb.WriteString(quoteEnvValue(formatEnvValue(rv)))
+		}
+		sb.WriteString("\n")
+	})
+
+	_, err := io.WriteString(p.writer, sb.String())
+	return err
+}
+
+// formatEnvValue formats rv in the text which the env source can bind
+// back to the field.
+func formatEnvValue(rv reflect.Value) string {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return ""
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Type() == typeOfTime {
+		return rv.Interface().(time.Time).Format(time.RFC3339Nano)
+	}
+	if rv.CanAddr() {
+		if v, ok := rv.Addr().Interface().(fmt.Stringer); ok {
+			return v.String()
+		}
+	}
+	if v, ok := rv.Interface().(fmt.Stringer); ok {
+		return v.String()
+	}
+
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return string(rv.Bytes())
+		}
+		elems := make([]string, rv.Len())
+		for i := range elems {
+			elems[i] = formatEnvValue(rv.Index(i))
+		}
+		return strings.Join(elems, ",")
+	}
+	return fmt.Sprintf("%v", rv.Interface())
+}
+
+// quoteEnvValue quotes v in the syntax shared by the .env files and the
+// shells. The line breaks are escaped in the .env syntax.
+func quoteEnvValue(v string) string {
+	if len(v) == 0 || plainEnvValuePattern.MatchString(v) {
+		return v
+	}
+	if !strings.ContainsAny(v, "'\n\r") {
+		return "'" + v + "'"
+	}
+
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		`"`, `\"`,
+		`$`, `\$`,
+		"`", "\\`",
+		"\n", `\n`,
+		"\r", `\r`,
+	)
+	return `"` + replacer.Replace(v) + `"`
+}
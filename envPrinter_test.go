@@ -0,0 +1,87 @@
+package config
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type envPrinterConfig struct {
+	RedisHost     string        `env:"REDIS_HOST"`
+	RedisPassword string        `env:"REDIS_PASSWORD"   secret:"true"`
+	RedisDB       int           `env:"REDIS_DB"`
+	Timeout       time.Duration `env:"TIMEOUT"`
+	Since         time.Time     `env:"SINCE"`
+	Tags          []string      `env:"TAG"`
+	Message       string        `env:"MESSAGE"`
+	Workspace     string        `env:"-"`
+}
+
+func TestEnvPrinter(t *testing.T) {
+	conf := envPrinterConfig{
+		RedisHost:     "127.0.0.1:6379",
+		RedisPassword: "1234",
+		RedisDB:       3,
+		Timeout:       3 * time.Second,
+		Since:         time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:          []string{"demo", "test"},
+		Message:       `it's "$HOME"`,
+		Workspace:     "demo",
+	}
+
+	var buffer bytes.Buffer
+	err := NewEnvPrinter(&buffer).
+		SetExport(true).
+		Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `export REDIS_HOST=127.0.0.1:6379
+# export REDIS_PASSWORD=******
+export REDIS_DB=3
+export TIMEOUT=3s
+export SINCE=2022-01-02T03:04:05Z
+export TAG=demo,test
+export MESSAGE="it's \"\$HOME\""
+`
+	if buffer.String() != expected {
+		t.Errorf("assert 'EnvPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+}
+
+func TestEnvPrinter_LoadDotEnvFile(t *testing.T) {
+	os.Clearenv()
+
+	conf := envPrinterConfig{
+		RedisHost: "127.0.0.1:6379",
+		RedisDB:   3,
+		Timeout:   3 * time.Second,
+		Since:     time.Date(2022, 1, 2, 3, 4, 5, 0, time.UTC),
+		Tags:      []string{"demo", "test"},
+		Message:   "it's \"$HOME\" #1 \\ `ok`",
+	}
+
+	var buffer bytes.Buffer
+	err := NewEnvPrinter(&buffer).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := filepath.Join(t.TempDir(), ".env.resolved")
+	err = os.WriteFile(path, buffer.Bytes(), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	actual := envPrinterConfig{}
+	NewConfigurationService(&actual).
+		LoadDotEnvFile(path)
+
+	if !reflect.DeepEqual(conf, actual) {
+		t.Errorf("assert 'envPrinterConfig':: expected '%#+v', got '%#+v'", conf, actual)
+	}
+}
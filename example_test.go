@@ -144,3 +144,112 @@ func ExampleConfigurationService_LoadFile() {
 	// Tags          = []
 	// Version       = ""
 }
+
+func ExampleExplainPrinter() {
+	os.Clearenv()
+	os.Setenv("REDIS_HOST", "127.0.0.3:6379")
+
+	conf := struct {
+		RedisHost     string `env:"REDIS_HOST"       yaml:"redisHost"`
+		RedisDB       int    `env:"REDIS_DB"         yaml:"redisDB"`
+		RedisPoolSize int    `env:"-"                yaml:"redisPoolSize"`
+	}{}
+
+	service := config.NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisDB: 3")).
+		LoadYamlBytes([]byte("redisDB: 12")).
+		LoadEnvironmentVariables("")
+
+	service.OutputWithPrinter(config.NewExplainPrinter(os.Stdout, service))
+	// Output:
+	// FIELD          VALUE             SOURCE          OVERRIDES
+	// RedisHost      "127.0.0.3:6379"  env REDIS_HOST  "127.0.0.1:6379" (yaml redisHost), "" (initial)
+	// RedisDB        12                yaml redisDB    3 (yaml redisDB), 0 (initial)
+	// RedisPoolSize  0                 -               -
+}
+
+func ExampleJsonPrinter() {
+	conf := struct {
+		RedisHost     string   `json:"redisHost"`
+		RedisPassword string   `json:"redisPassword"   secret:"true"`
+		RedisDB       int      `json:"redisDB"`
+		Tags          []string `json:"tags"`
+		HealthCheck   string   `json:"healthCheck"`
+	}{
+		RedisHost:     "127.0.0.1:6379",
+		RedisPassword: "1234",
+		RedisDB:       3,
+		Tags:          []string{"demo", "test"},
+		HealthCheck:   "http://127.0.0.1/health?db=3&timeout=5s",
+	}
+
+	config.NewJsonPrinter(os.Stdout).
+		SetIndent("", "  ").
+		Print(&conf)
+	// Output:
+	// {
+	//   "redisHost": "127.0.0.1:6379",
+	//   "redisPassword": "******",
+	//   "redisDB": 3,
+	//   "tags": [
+	//     "demo",
+	//     "test"
+	//   ],
+	//   "healthCheck": "http://127.0.0.1/health?db=3&timeout=5s"
+	// }
+}
+
+func ExampleYamlPrinter() {
+	type RedisConfig struct {
+		Host     string `yaml:"host"`
+		Password string `yaml:"password"   secret:"true"`
+		DB       int    `yaml:"db"`
+	}
+
+	conf := struct {
+		Redis     RedisConfig `yaml:"redis"`
+		Workspace string      `yaml:"workspace"`
+	}{
+		Redis: RedisConfig{
+			Host:     "127.0.0.1:6379",
+			Password: "1234",
+			DB:       3,
+		},
+		Workspace: "demo",
+	}
+
+	config.NewYamlPrinter(os.Stdout).
+		Print(&conf)
+	// Output:
+	// redis:
+	//   host: 127.0.0.1:6379
+	//   password: '******'
+	//   db: 3
+	// workspace: demo
+}
+
+func ExampleYamlPrinter_SetIndent() {
+	type RedisConfig struct {
+		Host string   `yaml:"host"`
+		Tags []string `yaml:"tags"`
+	}
+
+	conf := struct {
+		Redis RedisConfig `yaml:"redis"`
+	}{
+		Redis: RedisConfig{
+			Host: "127.0.0.1:6379",
+			Tags: []string{"demo", "test"},
+		},
+	}
+
+	config.NewYamlPrinter(os.Stdout).
+		SetIndent(4).
+		Print(&conf)
+	// Output:
+	// redis:
+	//     host: 127.0.0.1:6379
+	//     tags:
+	//         - demo
+	//         - test
+}
@@ -0,0 +1,62 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+var _ Printer = new(ExplainPrinter)
+
+// ExplainPrinter prints every field with its final value, the source
+// which assigned it, and the earlier values it overrode. The values of the
+// secret fields are masked.
+type ExplainPrinter struct {
+	writer  io.Writer
+	service *ConfigurationService
+}
+
+func NewExplainPrinter(writer io.Writer, service *ConfigurationService) *ExplainPrinter {
+	return &ExplainPrinter{
+		writer:  writer,
+		service: service,
+	}
+}
+
+func (p *ExplainPrinter) Print(target interface{}) error {
+	w := tabwriter.NewWriter(p.writer, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tVALUE\tSOURCE\tOVERRIDES")
+
+	reflectutil.WalkLeaves(reflect.ValueOf(target), func(path []reflect.StructField, rv reflect.Value) {
+		field := reflectutil.PathName(path)
+		history := p.service.History(field)
+
+		var (
+			source    = "-"
+			overrides = "-"
+		)
+		if len(history) > 0 {
+			source = history[len(history)-1].String()
+
+			entries := make([]string, 0, len(history))
+			for i := len(history) - 2; i >= 0; i-- {
+				entries = append(entries, fmt.Sprintf("%s (%s)", formatSecret(path, history[i].Value), history[i]))
+			}
+			entries = append(entries, fmt.Sprintf("%s (initial)", formatSecret(path, history[0].Previous)))
+			overrides = strings.Join(entries, ", ")
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", field, formatSecret(path, rv.Interface()), source, overrides)
+	})
+	return w.Flush()
+}
+
+func formatValue(v interface{}) string {
+	if s, ok := v.(string); ok {
+		return fmt.Sprintf("%q", s)
+	}
+	return fmt.Sprintf("%v", v)
+}
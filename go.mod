@@ -6,6 +6,7 @@ require (
 	github.com/Bofry/structproto v0.2.1
 	github.com/joho/godotenv v1.4.0
 	gopkg.in/yaml.v2 v2.4.0
+	gopkg.in/yaml.v3 v3.0.1
 )
 
 require (
@@ -0,0 +1,111 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+const (
+	hookAfterLoad = "AfterLoad"
+	hookValidate  = "Validate"
+)
+
+// invokeHooks calls fn on the target and its nested structs. The nested
+// structs are visited before the struct containing them.
+func invokeHooks(target interface{}, source string, fn func(v interface{}) error) []*FieldError {
+	var errs []*FieldError
+	visitHooks(nil, reflect.ValueOf(target), func(path []string, v interface{}) {
+		err := fn(v)
+		if err != nil {
+			for _, e := range makeFieldErrors(source, err) {
+				if len(e.Field) == 0 {
+					e.Field = strings.Join(path, ".")
+				}
+				errs = append(errs, e)
+			}
+		}
+	})
+	return errs
+}
+
+func visitHooks(path []string, rv reflect.Value, visit func(path []string, v interface{})) {
+	if rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return
+		}
+	} else if rv.CanAddr() {
+		rv = rv.Addr()
+	} else {
+		return
+	}
+
+	elem := rv.Elem()
+	if elem.Kind() == reflect.Struct {
+		t := elem.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+
+			inner := elem.Field(i)
+			innerType := inner.Type()
+			if innerType.Kind() == reflect.Ptr {
+				innerType = innerType.Elem()
+			}
+			if reflectutil.IsComposite(innerType) {
+				visitHooks(append(path[:len(path):len(path)], field.Name), inner, visit)
+			}
+		}
+	}
+	visit(path, rv.Interface())
+}
+
+// runHooks calls the Normalize() and AfterLoad() hooks once for the
+// values loaded so far. Loading another source makes them called again.
+func (service *ConfigurationService) runHooks() {
+	if service.hooked {
+		return
+	}
+	service.hooked = true
+	service.normalize()
+	service.hookErrors = service.afterLoad()
+}
+
+func (service *ConfigurationService) beforeLoad() {
+	invokeHooks(service.target, "", func(v interface{}) error {
+		if hook, ok := v.(BeforeLoader); ok {
+			hook.BeforeLoad()
+		}
+		return nil
+	})
+}
+
+func (service *ConfigurationService) normalize() {
+	invokeHooks(service.target, "", func(v interface{}) error {
+		if hook, ok := v.(Normalizer); ok {
+			hook.Normalize()
+		}
+		return nil
+	})
+}
+
+func (service *ConfigurationService) afterLoad() []*FieldError {
+	return invokeHooks(service.target, hookAfterLoad, func(v interface{}) error {
+		if hook, ok := v.(AfterLoader); ok {
+			return hook.AfterLoad()
+		}
+		return nil
+	})
+}
+
+func (service *ConfigurationService) validateHooks() []*FieldError {
+	return invokeHooks(service.target, hookValidate, func(v interface{}) error {
+		if hook, ok := v.(Validator); ok {
+			return hook.Validate()
+		}
+		return nil
+	})
+}
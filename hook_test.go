@@ -0,0 +1,121 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type hookRedisConfig struct {
+	Host string `yaml:"host"`
+	Port int    `yaml:"port"`
+	URL  string `yaml:"-"`
+}
+
+func (c *hookRedisConfig) Normalize() {
+	c.Host = strings.ToLower(c.Host)
+}
+
+func (c *hookRedisConfig) AfterLoad() error {
+	c.URL = fmt.Sprintf("redis://%s:%d", c.Host, c.Port)
+	return nil
+}
+
+type hookConfig struct {
+	Redis     hookRedisConfig `yaml:"redis"`
+	Workspace string          `yaml:"workspace"`
+
+	events []string
+}
+
+func (c *hookConfig) BeforeLoad() {
+	c.events = append(c.events, "BeforeLoad")
+}
+
+func (c *hookConfig) AfterLoad() error {
+	c.events = append(c.events, "AfterLoad:"+c.Redis.URL)
+	return nil
+}
+
+func (c *hookConfig) Validate() error {
+	if len(c.Workspace) == 0 {
+		return errors.New("workspace is empty")
+	}
+	return nil
+}
+
+func TestConfigurationService_WithHooks(t *testing.T) {
+	conf := hookConfig{}
+
+	err := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redis:\n  host: Redis.Local\n  port: 6379")).
+		Build()
+
+	var expectedURL = "redis://redis.local:6379"
+	if conf.Redis.URL != expectedURL {
+		t.Errorf("assert 'hookConfig.Redis.URL':: expected '%v', got '%v'", expectedURL, conf.Redis.URL)
+	}
+	var expectedEvents = []string{"BeforeLoad", "AfterLoad:" + expectedURL}
+	if strings.Join(conf.events, "|") != strings.Join(expectedEvents, "|") {
+		t.Errorf("assert 'hookConfig.events':: expected '%v', got '%v'", expectedEvents, conf.events)
+	}
+	var expectedMessage = "config: Validate: workspace is empty"
+	if err == nil || err.Error() != expectedMessage {
+		t.Errorf("assert 'ConfigurationService.Build()':: expected '%v', got '%v'", expectedMessage, err)
+	}
+}
+
+type countingHookConfig struct {
+	Host  string `yaml:"host"`
+	Extra string `yaml:"-"`
+}
+
+var countingHookCalls = map[string]int{}
+
+func (c *countingHookConfig) Normalize() {
+	countingHookCalls["Normalize"]++
+}
+
+func (c *countingHookConfig) AfterLoad() error {
+	countingHookCalls["AfterLoad"]++
+	return nil
+}
+
+func TestConfigurationService_WithHooks_CalledOnce(t *testing.T) {
+	countingHookCalls = map[string]int{}
+
+	conf := countingHookConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("host: a"))
+	conf.Extra = "manual"
+	service.
+		LoadYamlBytes([]byte("host: b")).
+		LoadYamlBytes([]byte("host: c"))
+
+	if len(countingHookCalls) != 0 {
+		t.Errorf("assert 'hook calls':: expected '%v', got '%v'", map[string]int{}, countingHookCalls)
+	}
+	for i := 0; i < 2; i++ {
+		err := service.Build()
+		if err != nil {
+			t.Errorf("assert 'ConfigurationService.Build()':: expected '%v', got '%v'", nil, err)
+		}
+	}
+	var expectedCalls = map[string]int{"Normalize": 1, "AfterLoad": 1}
+	if !reflect.DeepEqual(expectedCalls, countingHookCalls) {
+		t.Errorf("assert 'hook calls':: expected '%v', got '%v'", expectedCalls, countingHookCalls)
+	}
+	if conf.Extra != "manual" {
+		t.Errorf("assert 'countingHookConfig.Extra':: expected '%v', got '%v'", "manual", conf.Extra)
+	}
+
+	service.LoadYamlBytes([]byte("host: d"))
+	service.Build()
+	expectedCalls = map[string]int{"Normalize": 2, "AfterLoad": 2}
+	if !reflect.DeepEqual(expectedCalls, countingHookCalls) {
+		t.Errorf("assert 'hook calls':: expected '%v', got '%v'", expectedCalls, countingHookCalls)
+	}
+}
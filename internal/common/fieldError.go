@@ -0,0 +1,113 @@
+package common
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+const (
+	errStringValueLength = 24
+)
+
+var _ error = new(FieldError)
+
+// A FieldError represents a failure while assigning a value from a
+// configuration source to a field.
+type FieldError struct {
+	Source string
+	Key    string
+	Field  string
+	Value  interface{}
+	Err    error
+}
+
+func (e *FieldError) Error() string {
+	var parts []string
+	if len(e.Field) > 0 {
+		parts = append(parts, fmt.Sprintf("field '%s'", e.Field))
+	}
+	if len(e.Key) > 0 {
+		parts = append(parts, fmt.Sprintf("key '%s'", e.Key))
+	}
+	if e.Value != nil {
+		parts = append(parts, fmt.Sprintf("value '%s'", formatValue(e.Value)))
+	}
+
+	var sb strings.Builder
+	if len(e.Source) > 0 {
+		sb.WriteString(e.Source)
+		sb.WriteString(": ")
+	}
+	if len(parts) > 0 {
+		sb.WriteString(strings.Join(parts, ", "))
+		sb.WriteString(": ")
+	}
+	sb.WriteString(fmt.Sprintf("%v", e.Err))
+	return sb.String()
+}
+
+// Unwrap returns the underlying error.
+func (e *FieldError) Unwrap() error {
+	return e.Err
+}
+
+var _ error = FieldErrors(nil)
+
+type FieldErrors []*FieldError
+
+func (errs FieldErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// FieldErrorCollector gathers the FieldErrors reported while binding the
+// fields of a struct and sorts them in field declaration order.
+type FieldErrorCollector struct {
+	entries []fieldErrorEntry
+}
+
+type fieldErrorEntry struct {
+	index int
+	err   *FieldError
+}
+
+func (c *FieldErrorCollector) Add(index int, err *FieldError) {
+	c.entries = append(c.entries, fieldErrorEntry{index, err})
+}
+
+func (c *FieldErrorCollector) Err() error {
+	if len(c.entries) == 0 {
+		return nil
+	}
+
+	sort.SliceStable(c.entries, func(i, j int) bool {
+		return c.entries[i].index < c.entries[j].index
+	})
+
+	errs := make(FieldErrors, len(c.entries))
+	for i, entry := range c.entries {
+		errs[i] = entry.err
+	}
+	return errs
+}
+
+func formatValue(v interface{}) string {
+	var str string
+	switch v := v.(type) {
+	case string:
+		str = v
+	case []byte:
+		str = string(v)
+	default:
+		str = fmt.Sprintf("%v", v)
+	}
+
+	if len(str) > errStringValueLength {
+		return str[:errStringValueLength] + "..."
+	}
+	return str
+}
@@ -0,0 +1,39 @@
+package common
+
+import "fmt"
+
+// A Recorder is called by the loaders for each value they assign, even if
+// the value doesn't change, so the provenance includes the sources which
+// assign the same value again. The field is the path of the assigned
+// field, e.g. "RedisHost"; the key is the name of the value in the source,
+// e.g. "REDIS_HOST" or "redis.host". Either may be empty if the loader
+// doesn't know it.
+type Recorder func(field string, key string)
+
+// RecordKeys calls record with the path of every key of the decoded
+// document, e.g. "redis" and "redis.host". The sequences are treated as
+// single values.
+func RecordKeys(document interface{}, record Recorder) {
+	recordKeys("", document, record)
+}
+
+func recordKeys(path string, document interface{}, record Recorder) {
+	switch v := document.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			recordKey(path, key, value, record)
+		}
+	case map[interface{}]interface{}:
+		for key, value := range v {
+			recordKey(path, fmt.Sprint(key), value, record)
+		}
+	}
+}
+
+func recordKey(path string, key string, value interface{}, record Recorder) {
+	if len(path) > 0 {
+		key = path + "." + key
+	}
+	record("", key)
+	recordKeys(key, value, record)
+}
@@ -0,0 +1,111 @@
+package defaults
+
+import (
+	"flag"
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/reflectutil"
+	"github.com/Bofry/structproto/valuebinder"
+)
+
+const (
+	TagName = "default"
+)
+
+// Process assigns the values of the default tags to the zero value fields
+// of target, including the fields of nested structs. The nil pointers to
+// structs are allocated if any field below them has a default tag.
+func Process(target interface{}) error {
+	var errs common.FieldErrors
+	process(nil, reflectutil.Indirect(reflect.ValueOf(target)), &errs)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func process(path []string, rv reflect.Value, errs *common.FieldErrors) {
+	if !rv.IsValid() || rv.Kind() != reflect.Struct {
+		return
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], field.Name)
+		elem := rv.Field(i)
+
+		value, ok := field.Tag.Lookup(TagName)
+		if !ok {
+			switch elem.Kind() {
+			case reflect.Struct:
+				process(fieldPath, elem, errs)
+			case reflect.Ptr:
+				if !elem.IsNil() {
+					process(fieldPath, elem.Elem(), errs)
+				} else if hasDefaults(elem.Type().Elem(), make(map[reflect.Type]bool)) {
+					ptr := reflect.New(elem.Type().Elem())
+					process(fieldPath, ptr.Elem(), errs)
+					elem.Set(ptr)
+				}
+			}
+			continue
+		}
+
+		if !elem.IsZero() {
+			continue
+		}
+		err := bind(elem, value)
+		if err != nil {
+			*errs = append(*errs, &common.FieldError{
+				Key:   TagName,
+				Field: strings.Join(fieldPath, "."),
+				Value: value,
+				Err:   err,
+			})
+		}
+	}
+}
+
+// hasDefaults reports whether any field of the struct type t, including
+// the fields of nested structs, has a default tag.
+func hasDefaults(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if t.Kind() != reflect.Struct || visited[t] {
+		return false
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		if _, ok := field.Tag.Lookup(TagName); ok {
+			return true
+		}
+
+		fieldType := field.Type
+		if fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if hasDefaults(fieldType, visited) {
+			return true
+		}
+	}
+	return false
+}
+
+func bind(rv reflect.Value, value string) error {
+	if rv.CanAddr() {
+		if v, ok := rv.Addr().Interface().(flag.Value); ok {
+			return v.Set(value)
+		}
+	}
+	return valuebinder.StringBinder(rv).Bind(value)
+}
@@ -0,0 +1,101 @@
+package defaults
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+type Level int
+
+func (l *Level) String() string {
+	return [...]string{"debug", "info", "error"}[*l]
+}
+
+func (l *Level) Set(name string) error {
+	switch strings.ToLower(name) {
+	case "debug":
+		*l = 0
+	case "info":
+		*l = 1
+	case "error":
+		*l = 2
+	}
+	return nil
+}
+
+type redisConfig struct {
+	Host string `default:"127.0.0.1:6379"`
+	DB   int    `default:"3"`
+}
+
+type config struct {
+	Redis     redisConfig
+	Cache     *redisConfig
+	Workspace string        `default:"demo"`
+	Tags      []string      `default:"demo,test"`
+	Timeout   time.Duration `default:"3s"`
+	Level     Level         `default:"error"`
+	Ignored   string
+}
+
+func TestProcess(t *testing.T) {
+	c := config{
+		Cache:     &redisConfig{DB: 9},
+		Workspace: "demo_test",
+	}
+
+	err := Process(&c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := config{
+		Redis:     redisConfig{Host: "127.0.0.1:6379", DB: 3},
+		Cache:     &redisConfig{Host: "127.0.0.1:6379", DB: 9},
+		Workspace: "demo_test",
+		Tags:      []string{"demo", "test"},
+		Timeout:   3 * time.Second,
+		Level:     Level(2),
+	}
+	if !reflect.DeepEqual(expected, c) {
+		t.Errorf("assert 'config':: expected '%#+v', got '%#+v'", expected, c)
+	}
+}
+
+type node struct {
+	Name string
+	Next *node
+}
+
+func TestProcess_WithNilPointer(t *testing.T) {
+	c := struct {
+		Cache *redisConfig
+		Node  *node
+	}{}
+
+	err := Process(&c)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := &redisConfig{Host: "127.0.0.1:6379", DB: 3}
+	if !reflect.DeepEqual(expected, c.Cache) {
+		t.Errorf("assert 'config.Cache':: expected '%#+v', got '%#+v'", expected, c.Cache)
+	}
+	if c.Node != nil {
+		t.Errorf("assert 'config.Node':: expected '%v', got '%#+v'", nil, c.Node)
+	}
+}
+
+func TestProcess_WithInvalidValue(t *testing.T) {
+	c := struct {
+		Port int `default:"abc"`
+	}{}
+
+	err := Process(&c)
+	if err == nil {
+		t.Errorf("assert 'Process()':: expected error, got '%v'", err)
+	}
+}
@@ -3,9 +3,11 @@ package env
 import (
 	"os"
 	"strings"
+	"sync"
 
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/expand"
 	"github.com/Bofry/structproto"
-	"github.com/Bofry/structproto/valuebinder"
 	"github.com/joho/godotenv"
 )
 
@@ -13,7 +15,11 @@ const (
 	TagName = "env"
 )
 
-func Process(prefix string, target interface{}) error {
+// dotEnvMutex serializes the checks and the writes of the environment
+// variables by the .env files.
+var dotEnvMutex sync.Mutex
+
+func Process(prefix string, target interface{}, record common.Recorder) error {
 	if len(prefix) > 0 {
 		prefix += "_"
 	}
@@ -25,38 +31,89 @@ func Process(prefix string, target interface{}) error {
 		return err
 	}
 
-	var table structproto.FieldValueMap = make(structproto.FieldValueMap)
+	var values = make(map[string]string)
 	for _, e := range os.Environ() {
 		parts := strings.SplitN(e, "=", 2)
-		name, value := parts[0], parts[1]
-		if strings.HasPrefix(name, prefix) {
-			table[name[len(prefix):]] = value
-		}
+		values[parts[0]] = parts[1]
 	}
-	err = prototype.BindIterator(table, valuebinder.BuildStringBinder)
+	return prototype.Bind(&EnvBinder{
+		Prefix:   prefix,
+		Values:   values,
+		Recorder: record,
+	})
+}
+
+func LoadDotEnv(written map[string]string, target interface{}, record common.Recorder) error {
+	return LoadDotEnvFile(".env", written, target, record)
+}
+
+// LoadDotEnvFile loads the .env file into the environment, then assigns
+// the environment variables to target. Only the variables of the .env
+// file are passed to record. The variables set are kept in written, so
+// the file can be loaded again with the same written to apply its
+// changes; written can be nil.
+func LoadDotEnvFile(filepath string, written map[string]string, target interface{}, record common.Recorder) error {
+	path, err := expand.ExpandEnv(filepath)
 	if err != nil {
-		return err
+		return &common.FieldError{
+			Key: filepath,
+			Err: err,
+		}
 	}
-	return nil
-}
 
-func LoadDotEnv(target interface{}) error {
-	var err error
-	err = godotenv.Load()
+	values, err := loadDotEnvFile(path, written)
 	if err != nil {
-		return err
+		if os.IsNotExist(err) {
+			return err
+		}
+		return &common.FieldError{
+			Key: path,
+			Err: err,
+		}
 	}
 
-	return Process("", target)
+	if record != nil {
+		record = recordKeys(values, record)
+	}
+	return Process("", target, record)
 }
 
-func LoadDotEnvFile(filepath string, target interface{}) error {
-	var err error
-	path := os.ExpandEnv(filepath)
-	err = godotenv.Load(path)
+// loadDotEnvFile sets the variables of the .env file which don't exist in
+// the environment, or still hold the values in written, i.e. the values
+// set by the same file before. So the first file loaded wins, and the
+// changes of the file can be applied again without overriding the
+// variables set by others in the meantime.
+func loadDotEnvFile(path string, written map[string]string) (map[string]string, error) {
+	values, err := godotenv.Read(path)
 	if err != nil {
-		return err
+		return nil, err
+	}
+
+	dotEnvMutex.Lock()
+	defer dotEnvMutex.Unlock()
+
+	for key, value := range values {
+		if current, ok := os.LookupEnv(key); ok {
+			if previous, owned := written[key]; !owned || previous != current {
+				continue
+			}
+		}
+		err = os.Setenv(key, value)
+		if err != nil {
+			return nil, err
+		}
+		if written != nil {
+			written[key] = value
+		}
 	}
+	return values, nil
+}
 
-	return Process("", target)
+// recordKeys passes the assignments of the specified keys only to record.
+func recordKeys(values map[string]string, record common.Recorder) common.Recorder {
+	return func(field string, key string) {
+		if _, ok := values[key]; ok {
+			record(field, key)
+		}
+	}
 }
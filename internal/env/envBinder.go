@@ -0,0 +1,59 @@
+package env
+
+import (
+	"reflect"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/structproto"
+	"github.com/Bofry/structproto/valuebinder"
+)
+
+var _ structproto.StructBinder = new(EnvBinder)
+
+type EnvBinder struct {
+	Prefix string
+	Values map[string]string
+	// Recorder, if not nil, is called for each assigned field.
+	Recorder common.Recorder
+
+	errors common.FieldErrorCollector
+}
+
+func (p *EnvBinder) Init(context *structproto.StructProtoContext) error {
+	return nil
+}
+
+func (p *EnvBinder) Bind(field structproto.FieldInfo, rv reflect.Value) error {
+	name := p.Prefix + field.Name()
+
+	value, ok := p.Values[name]
+	if !ok {
+		if field.HasFlag(structproto.RequiredFlag) {
+			p.errors.Add(field.Index(), &common.FieldError{
+				Key:   name,
+				Field: field.IDName(),
+				Err:   &structproto.MissingRequiredFieldError{Field: name},
+			})
+		}
+		return nil
+	}
+
+	err := valuebinder.StringBinder(rv).Bind(value)
+	if err != nil {
+		p.errors.Add(field.Index(), &common.FieldError{
+			Key:   name,
+			Field: field.IDName(),
+			Value: value,
+			Err:   err,
+		})
+		return nil
+	}
+	if p.Recorder != nil {
+		p.Recorder(field.IDName(), name)
+	}
+	return nil
+}
+
+func (p *EnvBinder) Deinit(context *structproto.StructProtoContext) error {
+	return p.errors.Err()
+}
@@ -4,6 +4,8 @@ import (
 	"os"
 	"reflect"
 	"testing"
+
+	"github.com/Bofry/config/internal/common"
 )
 
 type config struct {
@@ -23,7 +25,7 @@ func TestLoad(t *testing.T) {
 	t.Setenv("TAG", "demo,test")
 
 	c := config{}
-	err := Process("", &c)
+	err := Process("", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -48,7 +50,7 @@ func TestLoad_WithPrefix(t *testing.T) {
 	t.Setenv("K8S_WORKSPACE", "demo_test")
 
 	c := config{}
-	err := Process("K8S", &c)
+	err := Process("K8S", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -67,7 +69,7 @@ func TestLoad_WithPrefix(t *testing.T) {
 func TestLoadDotEnv(t *testing.T) {
 	os.Clearenv()
 	c := config{}
-	err := LoadDotEnv(&c)
+	err := LoadDotEnv(nil, &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -90,7 +92,7 @@ func TestLoadDotEnvFile(t *testing.T) {
 	os.Setenv("ENVIRONMENT", "local")
 
 	c := config{}
-	err := LoadDotEnvFile(".env.${ENVIRONMENT}", &c)
+	err := LoadDotEnvFile(".env.${ENVIRONMENT}", nil, &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -107,3 +109,118 @@ func TestLoadDotEnvFile(t *testing.T) {
 		t.Errorf("assert 'config':: expected '%#+v', got '%#+v'", expected, c)
 	}
 }
+
+func TestLoad_WithInvalidValues(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_HOST", "192.168.56.53")
+	t.Setenv("REDIS_DB", "abc")
+
+	c := config{}
+	err := Process("", &c, nil)
+	if err == nil {
+		t.Fatalf("assert 'Process()':: expected error, got '%v'", err)
+	}
+
+	errs, ok := err.(common.FieldErrors)
+	if !ok {
+		t.Fatalf("assert 'Process()':: expected '%T', got '%T'", errs, err)
+	}
+	var expectedFields = []string{"RedisDB", "Workspace"}
+	if len(errs) != len(expectedFields) {
+		t.Fatalf("assert 'FieldErrors':: expected '%v' errors, got '%v'", len(expectedFields), errs)
+	}
+	for i, expectedField := range expectedFields {
+		if errs[i].Field != expectedField {
+			t.Errorf("assert 'FieldErrors[%d].Field':: expected '%v', got '%v'", i, expectedField, errs[i].Field)
+		}
+	}
+	var expectedRedisHost = "192.168.56.53"
+	if c.RedisHost != expectedRedisHost {
+		t.Errorf("assert 'config.RedisHost':: expected '%v', got '%v'", expectedRedisHost, c.RedisHost)
+	}
+}
+
+func TestLoadDotEnvFile_WithOverriddenValue(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("WORKSPACE", "demo_test")
+
+	filename := t.TempDir() + "/.env"
+	written := make(map[string]string)
+	for _, tc := range []struct {
+		content  string
+		override string
+		expected string
+	}{
+		{content: "REDIS_HOST=10.10.171.6", expected: "10.10.171.6"},
+		{content: "REDIS_HOST=10.10.171.7", expected: "10.10.171.7"},
+		{content: "REDIS_HOST=10.10.171.8", override: "192.168.56.53", expected: "192.168.56.53"},
+	} {
+		if len(tc.override) > 0 {
+			os.Setenv("REDIS_HOST", tc.override)
+		}
+		err := os.WriteFile(filename, []byte(tc.content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		c := config{}
+		err = LoadDotEnvFile(filename, written, &c, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		if c.RedisHost != tc.expected {
+			t.Errorf("assert 'config.RedisHost':: expected '%v', got '%v'", tc.expected, c.RedisHost)
+		}
+	}
+}
+
+func TestLoadDotEnvFile_WithMultipleFiles(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("WORKSPACE", "demo_test")
+
+	dir := t.TempDir()
+	first, second := dir+"/a.env", dir+"/b.env"
+	err := os.WriteFile(first, []byte("REDIS_HOST=10.10.171.6"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(second, []byte("REDIS_HOST=10.10.171.7\nREDIS_DB=3"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		firstWritten  = make(map[string]string)
+		secondWritten = make(map[string]string)
+	)
+	load := func() config {
+		c := config{}
+		err := LoadDotEnvFile(first, firstWritten, &c, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		err = LoadDotEnvFile(second, secondWritten, &c, nil)
+		if err != nil {
+			t.Error(err)
+		}
+		return c
+	}
+
+	c := load()
+	if c.RedisHost != "10.10.171.6" {
+		t.Errorf("assert 'config.RedisHost':: expected '%v', got '%v'", "10.10.171.6", c.RedisHost)
+	}
+	if c.RedisDB != 3 {
+		t.Errorf("assert 'config.RedisDB':: expected '%v', got '%v'", 3, c.RedisDB)
+	}
+
+	// the first file still wins after its changes are applied again
+	err = os.WriteFile(first, []byte("REDIS_HOST=10.10.171.8"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	c = load()
+	if c.RedisHost != "10.10.171.8" {
+		t.Errorf("assert 'config.RedisHost':: expected '%v', got '%v'", "10.10.171.8", c.RedisHost)
+	}
+}
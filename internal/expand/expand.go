@@ -0,0 +1,213 @@
+package expand
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Policy decides how a missing variable is expanded. A variable is
+// missing if it is unset or empty.
+type Policy int
+
+const (
+	PolicyError Policy = iota // fail with a *MissingVariableError
+	PolicyEmpty               // expand to the empty string
+	PolicyKeep                // keep the reference literally, e.g. "${HOST}"
+)
+
+// A MissingVariableError reports a variable which is missing, or refused
+// by the ${VAR:?message} form.
+type MissingVariableError struct {
+	Name    string
+	Message string
+}
+
+func (e *MissingVariableError) Error() string {
+	if len(e.Message) > 0 {
+		return fmt.Sprintf("environment variable '%s': %s", e.Name, e.Message)
+	}
+	return fmt.Sprintf("missing environment variable '%s'", e.Name)
+}
+
+// MissingVariableErrors lists every missing variable of an expansion.
+type MissingVariableErrors []*MissingVariableError
+
+func (errs MissingVariableErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// LookupFunc returns the value of the variable and reports whether it is
+// set, like os.LookupEnv().
+type LookupFunc func(name string) (string, bool)
+
+// Expand replaces the variable references in s by lookup. Besides $VAR and
+// ${VAR}, it supports the POSIX forms:
+//
+//	${VAR:-word}  word if VAR is unset or empty, otherwise VAR
+//	${VAR-word}   word if VAR is unset, otherwise VAR
+//	${VAR:+word}  empty if VAR is unset or empty, otherwise word
+//	${VAR+word}   empty if VAR is unset, otherwise word
+//	${VAR:?word}  fail with word if VAR is unset or empty, otherwise VAR
+//	${VAR?word}   fail with word if VAR is unset, otherwise VAR
+//
+// The word is expanded as well. The missing variables of $VAR and ${VAR}
+// are expanded by policy. It returns MissingVariableErrors listing every
+// failure, if any.
+func Expand(s string, lookup LookupFunc, policy Policy) (string, error) {
+	e := &expander{
+		lookup: lookup,
+		policy: policy,
+	}
+	v := e.expand(s)
+	if len(e.errors) > 0 {
+		return v, e.errors
+	}
+	return v, nil
+}
+
+// ExpandEnv expands the environment variables in s like Expand(), the
+// missing variables are expanded to the empty string like os.ExpandEnv().
+func ExpandEnv(s string) (string, error) {
+	return Expand(s, os.LookupEnv, PolicyEmpty)
+}
+
+type expander struct {
+	lookup LookupFunc
+	policy Policy
+	errors MissingVariableErrors
+}
+
+func (e *expander) expand(s string) string {
+	var sb strings.Builder
+	for i := 0; i < len(s); {
+		if s[i] != '$' || i+1 == len(s) {
+			sb.WriteByte(s[i])
+			i++
+			continue
+		}
+
+		switch {
+		case s[i+1] == '{':
+			end := closingBrace(s, i+2)
+			if end < 0 {
+				sb.WriteString(s[i:])
+				return sb.String()
+			}
+			sb.WriteString(e.expandBraced(s[i:end+1], s[i+2:end]))
+			i = end + 1
+		case isNameChar(s[i+1]):
+			end := i + 1
+			for end < len(s) && isNameChar(s[end]) {
+				end++
+			}
+			sb.WriteString(e.expandName(s[i:end], s[i+1:end]))
+			i = end
+		default:
+			sb.WriteByte(s[i])
+			i++
+		}
+	}
+	return sb.String()
+}
+
+// expandBraced expands the reference ref, whose content between the
+// braces is expr.
+func (e *expander) expandBraced(ref, expr string) string {
+	n := 0
+	for n < len(expr) && isNameChar(expr[n]) {
+		n++
+	}
+	name, rest := expr[:n], expr[n:]
+	if len(name) == 0 {
+		return ref
+	}
+	if len(rest) == 0 {
+		return e.expandName(ref, name)
+	}
+
+	colon := strings.HasPrefix(rest, ":")
+	if colon {
+		rest = rest[1:]
+	}
+	if len(rest) == 0 {
+		return ref
+	}
+	op, word := rest[0], rest[1:]
+
+	value, ok := e.lookup(name)
+	missing := !ok || (colon && len(value) == 0)
+	switch op {
+	case '-':
+		if missing {
+			return e.expand(word)
+		}
+		return value
+	case '+':
+		if missing {
+			return ""
+		}
+		return e.expand(word)
+	case '?':
+		if missing {
+			message := e.expand(word)
+			if len(message) == 0 {
+				message = "parameter null or not set"
+			}
+			e.errors = append(e.errors, &MissingVariableError{
+				Name:    name,
+				Message: message,
+			})
+			return ""
+		}
+		return value
+	}
+	return ref
+}
+
+func (e *expander) expandName(ref, name string) string {
+	value, ok := e.lookup(name)
+	if ok && len(value) > 0 {
+		return value
+	}
+
+	switch e.policy {
+	case PolicyKeep:
+		return ref
+	case PolicyError:
+		e.errors = append(e.errors, &MissingVariableError{
+			Name: name,
+		})
+	}
+	return ""
+}
+
+// closingBrace returns the index of the brace closing the reference whose
+// content starts at start, or -1.
+func closingBrace(s string, start int) int {
+	depth := 1
+	for i := start; i < len(s); i++ {
+		switch {
+		case s[i] == '$' && i+1 < len(s) && s[i+1] == '{':
+			depth++
+			i++
+		case s[i] == '}':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+func isNameChar(c byte) bool {
+	return c == '_' ||
+		('0' <= c && c <= '9') ||
+		('a' <= c && c <= 'z') ||
+		('A' <= c && c <= 'Z')
+}
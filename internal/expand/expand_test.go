@@ -0,0 +1,60 @@
+package expand
+
+import (
+	"testing"
+)
+
+func TestExpand(t *testing.T) {
+	env := map[string]string{
+		"HOST":  "127.0.0.1",
+		"PORT":  "6379",
+		"EMPTY": "",
+	}
+	lookup := func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+
+	cases := []struct {
+		input    string
+		policy   Policy
+		expected string
+		errors   int
+	}{
+		{"${HOST}:$PORT", PolicyError, "127.0.0.1:6379", 0},
+		{"${HOST:-localhost}", PolicyError, "127.0.0.1", 0},
+		{"${MISSING:-localhost}", PolicyError, "localhost", 0},
+		{"${EMPTY:-localhost}", PolicyError, "localhost", 0},
+		{"${EMPTY-localhost}", PolicyError, "", 0},
+		{"${MISSING:-$HOST:${PORT}}", PolicyError, "127.0.0.1:6379", 0},
+		{"${HOST:+enabled}", PolicyError, "enabled", 0},
+		{"${MISSING:+enabled}", PolicyError, "", 0},
+		{"${EMPTY+enabled}", PolicyError, "enabled", 0},
+		{"${HOST:?host is required}", PolicyError, "127.0.0.1", 0},
+		{"${MISSING:?host is required}", PolicyEmpty, "", 1},
+		{"$MISSING/${MISSING}", PolicyError, "/", 2},
+		{"$MISSING/${MISSING}", PolicyEmpty, "/", 0},
+		{"$MISSING/${MISSING}", PolicyKeep, "$MISSING/${MISSING}", 0},
+		{"$100 ${HOST", PolicyKeep, "$100 ${HOST", 0},
+		{"price: $ 100", PolicyError, "price: $ 100", 0},
+	}
+	for _, c := range cases {
+		actual, err := Expand(c.input, lookup, c.policy)
+		if actual != c.expected {
+			t.Errorf("assert 'Expand(%q)':: expected '%v', got '%v'", c.input, c.expected, actual)
+		}
+		errs, _ := err.(MissingVariableErrors)
+		if len(errs) != c.errors {
+			t.Errorf("assert 'Expand(%q)':: expected '%v' errors, got '%v'", c.input, c.errors, err)
+		}
+	}
+}
+
+func TestMissingVariableError(t *testing.T) {
+	_, err := Expand("${HOST:?host is required}", func(name string) (string, bool) { return "", false }, PolicyError)
+
+	var expected = "environment variable 'HOST': host is required"
+	if err == nil || err.Error() != expected {
+		t.Errorf("assert 'MissingVariableError':: expected '%v', got '%v'", expected, err)
+	}
+}
@@ -2,7 +2,9 @@ package flag
 
 import (
 	"flag"
+	"reflect"
 
+	"github.com/Bofry/config/internal/common"
 	"github.com/Bofry/structproto"
 )
 
@@ -12,16 +14,61 @@ const (
 
 var (
 	help = flag.Bool("help", false, "Show this help")
-
-	binder = &FlagBinder{}
 )
 
 func Process(target interface{}) error {
+	_, err := Parse(target, nil)
+	return err
+}
+
+// Parse is like Process, but also returns the raw values of the parsed
+// arguments, which can be assigned to another target by Bind().
+func Parse(target interface{}, record common.Recorder) (map[string]string, error) {
+	prototype, err := structproto.Prototypify(target, &structproto.StructProtoResolveOption{
+		TagName: TagName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	binder := &FlagBinder{
+		Recorder: record,
+	}
+	err = prototype.Bind(binder)
+	return binder.Values, err
+}
+
+// Bind assigns the raw values returned by Parse() to target without
+// registering the command line flags again.
+func Bind(target interface{}, values map[string]string, record common.Recorder) error {
 	prototype, err := structproto.Prototypify(target, &structproto.StructProtoResolveOption{
 		TagName: TagName,
 	})
 	if err != nil {
 		return err
 	}
-	return prototype.Bind(binder)
+
+	var (
+		binder = &FlagBinder{}
+		errors common.FieldErrorCollector
+	)
+	prototype.Map(func(field structproto.FieldInfo, rv reflect.Value) error {
+		if v, ok := values[field.Name()]; ok {
+			err := binder.makeFlagValue(rv).Set(v)
+			if err != nil {
+				errors.Add(field.Index(), &common.FieldError{
+					Key:   field.Name(),
+					Field: field.IDName(),
+					Value: v,
+					Err:   err,
+				})
+				return nil
+			}
+			if record != nil {
+				record(field.IDName(), field.Name())
+			}
+		}
+		return nil
+	})
+	return errors.Err()
 }
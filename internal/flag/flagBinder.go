@@ -5,19 +5,34 @@ import (
 	"os"
 	"reflect"
 
+	"github.com/Bofry/config/internal/common"
 	"github.com/Bofry/structproto"
 )
 
 var _ structproto.StructBinder = new(FlagBinder)
 
-type FlagBinder struct{}
+type FlagBinder struct {
+	// Values holds the raw values of the parsed arguments.
+	Values map[string]string
+	// Recorder, if not nil, is called for each assigned field.
+	Recorder common.Recorder
+
+	errors common.FieldErrorCollector
+}
 
 func (p *FlagBinder) Init(context *structproto.StructProtoContext) error {
+	p.Values = make(map[string]string)
 	return nil
 }
 
 func (p *FlagBinder) Bind(field structproto.FieldInfo, rv reflect.Value) error {
-	value := p.makeFlagValue(rv)
+	value := &flagValueRecorder{
+		Value:  p.makeFlagValue(rv),
+		field:  field,
+		values: p.Values,
+		record: p.Recorder,
+		errors: &p.errors,
+	}
 	flag.Var(value, field.Name(), field.Desc())
 	return nil
 }
@@ -32,7 +47,7 @@ func (p *FlagBinder) Deinit(context *structproto.StructProtoContext) error {
 		flag.Usage()
 		os.Exit(0)
 	}
-	return nil
+	return p.errors.Err()
 }
 
 func (p *FlagBinder) makeFlagValue(rv reflect.Value) flag.Value {
@@ -0,0 +1,48 @@
+package flag
+
+import (
+	"flag"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/structproto"
+)
+
+var _ flag.Value = new(flagValueRecorder)
+
+// flagValueRecorder records the raw values and the failures of the
+// underlying flag.Value rather than aborting the parsing, so all invalid
+// arguments can be reported at once.
+type flagValueRecorder struct {
+	flag.Value
+
+	field  structproto.FieldInfo
+	values map[string]string
+	record common.Recorder
+	errors *common.FieldErrorCollector
+}
+
+func (r *flagValueRecorder) Set(v string) error {
+	r.values[r.field.Name()] = v
+
+	err := r.Value.Set(v)
+	if err != nil {
+		r.errors.Add(r.field.Index(), &common.FieldError{
+			Key:   r.field.Name(),
+			Field: r.field.IDName(),
+			Value: v,
+			Err:   err,
+		})
+		return nil
+	}
+	if r.record != nil {
+		r.record(r.field.IDName(), r.field.Name())
+	}
+	return nil
+}
+
+func (r *flagValueRecorder) IsBoolFlag() bool {
+	if v, ok := r.Value.(interface{ IsBoolFlag() bool }); ok {
+		return v.IsBoolFlag()
+	}
+	return false
+}
@@ -89,3 +89,43 @@ func TestLoad(t *testing.T) {
 		t.Errorf("assert 'config':: expected '%#+v', got '%#+v'", expected, c)
 	}
 }
+
+func TestBind(t *testing.T) {
+	os.Args = []string{"example",
+		"--redis-host", "192.168.56.53:6379",
+		"--role", "Admin",
+	}
+
+	flag.CommandLine = flag.NewFlagSet(os.Args[0], flag.ExitOnError)
+
+	c := config{}
+	values, err := Parse(&c, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expectedValues := map[string]string{
+		"redis-host": "192.168.56.53:6379",
+		"role":       "Admin",
+	}
+	if !reflect.DeepEqual(expectedValues, values) {
+		t.Errorf("assert 'Parse()':: expected '%#+v', got '%#+v'", expectedValues, values)
+	}
+
+	another := config{
+		RedisDB: 3,
+	}
+	err = Bind(&another, values, nil)
+	if err != nil {
+		t.Error(err)
+	}
+
+	expected := config{
+		RedisHost: "192.168.56.53:6379",
+		RedisDB:   3,
+		Role:      ROLE_ADMIN,
+	}
+	if !reflect.DeepEqual(expected, another) {
+		t.Errorf("assert 'config':: expected '%#+v', got '%#+v'", expected, another)
+	}
+}
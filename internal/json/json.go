@@ -2,24 +2,121 @@ package json
 
 import (
 	"encoding/json"
+	"errors"
 	"io/ioutil"
-	"os"
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/expand"
 )
 
-func LoadFile(filepath string, target interface{}) error {
-	path := os.ExpandEnv(filepath)
+func LoadFile(filepath string, target interface{}, record common.Recorder) error {
+	path, err := expand.ExpandEnv(filepath)
+	if err != nil {
+		return &common.FieldError{
+			Key: filepath,
+			Err: err,
+		}
+	}
+
 	buffer, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	return LoadBytes(buffer, target)
+	err = LoadBytes(buffer, target, record)
+	if err != nil {
+		if errs, ok := err.(common.FieldErrors); ok {
+			for _, e := range errs {
+				e.Key = path
+			}
+		}
+		return err
+	}
+	return nil
 }
 
-func LoadBytes(buffer []byte, target interface{}) error {
+func LoadBytes(buffer []byte, target interface{}, record common.Recorder) error {
 	err := json.Unmarshal(buffer, target)
 	if err != nil {
-		return err
+		return toFieldErrors(target, err)
+	}
+
+	if record != nil {
+		var document interface{}
+		if json.Unmarshal(buffer, &document) == nil {
+			common.RecordKeys(document, record)
+		}
 	}
 	return nil
 }
+
+func toFieldErrors(target interface{}, err error) error {
+	var typeError *json.UnmarshalTypeError
+	if errors.As(err, &typeError) {
+		return common.FieldErrors{
+			&common.FieldError{
+				Key:   typeError.Field,
+				Field: resolveField(reflect.TypeOf(target), typeError.Field),
+				Value: typeError.Value,
+				Err:   err,
+			},
+		}
+	}
+	return common.FieldErrors{
+		&common.FieldError{
+			Err: err,
+		},
+	}
+}
+
+// resolveField resolves the path of the field which the key path of the
+// document, e.g. "redis.host", is assigned to, e.g. "Redis.Host". The
+// elements of arrays, slices, and maps are not indexed. It returns the
+// empty string if any key can't be resolved.
+func resolveField(t reflect.Type, key string) string {
+	if len(key) == 0 {
+		return ""
+	}
+
+	var path []string
+	for _, name := range strings.Split(key, ".") {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct {
+			return ""
+		}
+
+		field, ok := lookupField(t, name)
+		if !ok {
+			return ""
+		}
+		path = append(path, field.Name)
+		t = field.Type
+	}
+	return strings.Join(path, ".")
+}
+
+// lookupField finds the field of the struct type t named by the json tag
+// or the field name, ignoring case like json.Unmarshal().
+func lookupField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		tagName := strings.SplitN(field.Tag.Get("json"), ",", 2)[0]
+		if tagName == "-" {
+			continue
+		}
+		if len(tagName) == 0 {
+			tagName = field.Name
+		}
+		if strings.EqualFold(tagName, name) {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
@@ -0,0 +1,23 @@
+package keyvalue
+
+import (
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/env"
+	"github.com/Bofry/structproto"
+)
+
+// Process assigns values to the fields of target by their keys in the
+// specified tag, e.g. `consul:"REDIS_HOST"`.
+func Process(tagName string, values map[string]string, target interface{}, record common.Recorder) error {
+	prototype, err := structproto.Prototypify(target, &structproto.StructProtoResolveOption{
+		TagName: tagName,
+	})
+	if err != nil {
+		return err
+	}
+
+	return prototype.Bind(&env.EnvBinder{
+		Values:   values,
+		Recorder: record,
+	})
+}
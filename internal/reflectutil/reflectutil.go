@@ -0,0 +1,249 @@
+package reflectutil
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+type (
+	// LeafVisitor is called with the path of a leaf field and its values
+	// in both compared structs.
+	LeafVisitor func(path []reflect.StructField, x, y reflect.Value)
+)
+
+// DeepCopy returns a copy of rv which shares no pointer, slice, or map
+// with it.
+func DeepCopy(rv reflect.Value) reflect.Value {
+	if !rv.IsValid() {
+		return rv
+	}
+
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		container := reflect.New(rv.Type().Elem())
+		container.Elem().Set(DeepCopy(rv.Elem()))
+		return container
+	case reflect.Interface:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		container := reflect.New(rv.Type()).Elem()
+		container.Set(DeepCopy(rv.Elem()))
+		return container
+	case reflect.Struct:
+		container := reflect.New(rv.Type()).Elem()
+		container.Set(rv)
+		for i := 0; i < rv.NumField(); i++ {
+			if !container.Field(i).CanSet() {
+				continue
+			}
+			container.Field(i).Set(DeepCopy(rv.Field(i)))
+		}
+		return container
+	case reflect.Slice:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		container := reflect.MakeSlice(rv.Type(), rv.Len(), rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			container.Index(i).Set(DeepCopy(rv.Index(i)))
+		}
+		return container
+	case reflect.Array:
+		container := reflect.New(rv.Type()).Elem()
+		for i := 0; i < rv.Len(); i++ {
+			container.Index(i).Set(DeepCopy(rv.Index(i)))
+		}
+		return container
+	case reflect.Map:
+		if rv.IsNil() {
+			return reflect.Zero(rv.Type())
+		}
+		container := reflect.MakeMapWithSize(rv.Type(), rv.Len())
+		iter := rv.MapRange()
+		for iter.Next() {
+			container.SetMapIndex(DeepCopy(iter.Key()), DeepCopy(iter.Value()))
+		}
+		return container
+	}
+	return rv
+}
+
+// Indirect returns the struct value which rv points to.
+func Indirect(rv reflect.Value) reflect.Value {
+	for rv.Kind() == reflect.Ptr || rv.Kind() == reflect.Interface {
+		if rv.IsNil() {
+			return reflect.Value{}
+		}
+		rv = rv.Elem()
+	}
+	return rv
+}
+
+// IsComposite reports whether the struct type t can be walked through
+// field by field. Structs with unexported fields, like time.Time, are
+// treated as a single value.
+func IsComposite(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t.NumField() == 0 {
+		return false
+	}
+	for i := 0; i < t.NumField(); i++ {
+		if len(t.Field(i).PkgPath) > 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// WalkLeaves visits the leaf fields of the struct rv.
+func WalkLeaves(rv reflect.Value, visit func(path []reflect.StructField, rv reflect.Value)) {
+	walkLeaves(nil, Indirect(rv), visit)
+}
+
+// CompareLeaves visits the leaf fields which differ between the structs
+// x and y of the same type.
+func CompareLeaves(x, y reflect.Value, visit LeafVisitor) {
+	compareLeaves(nil, Indirect(x), Indirect(y), visit)
+}
+
+// PathName joins the names of path with period.
+func PathName(path []reflect.StructField) string {
+	names := make([]string, len(path))
+	for i, field := range path {
+		names[i] = field.Name
+	}
+	return strings.Join(names, ".")
+}
+
+func walkLeaves(path []reflect.StructField, rv reflect.Value, visit func(path []reflect.StructField, rv reflect.Value)) {
+	if !rv.IsValid() {
+		return
+	}
+
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], field)
+		elem := rv.Field(i)
+		if inner, ok := composite(elem); ok {
+			walkLeaves(fieldPath, inner, visit)
+			continue
+		}
+		visit(fieldPath, elem)
+	}
+}
+
+func compareLeaves(path []reflect.StructField, x, y reflect.Value, visit LeafVisitor) {
+	if !x.IsValid() || !y.IsValid() {
+		return
+	}
+
+	t := x.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+
+		fieldPath := append(path[:len(path):len(path)], field)
+		xelem, yelem := x.Field(i), y.Field(i)
+
+		xinner, xok := composite(xelem)
+		yinner, yok := composite(yelem)
+		if xok && yok {
+			compareLeaves(fieldPath, xinner, yinner, visit)
+			continue
+		}
+		if !reflect.DeepEqual(xelem.Interface(), yelem.Interface()) {
+			visit(fieldPath, xelem, yelem)
+		}
+	}
+}
+
+func composite(rv reflect.Value) (reflect.Value, bool) {
+	switch rv.Kind() {
+	case reflect.Struct:
+		if IsComposite(rv.Type()) {
+			return rv, true
+		}
+	case reflect.Ptr:
+		if !rv.IsNil() && IsComposite(rv.Type().Elem()) {
+			return rv.Elem(), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// ReplaceStrings visits the strings reachable from rv through the
+// exported struct fields, pointers, interfaces, slices, arrays, and map
+// values, and replaces each with the result of replace. The path names the
+// string like "Redis.Hosts[0]" or "Headers[X-Token]".
+func ReplaceStrings(rv reflect.Value, replace func(path string, s string) string) {
+	replaceStrings("", rv, replace, make(map[uintptr]bool))
+}
+
+func replaceStrings(path string, rv reflect.Value, replace func(path string, s string) string, visited map[uintptr]bool) {
+	switch rv.Kind() {
+	case reflect.String:
+		if rv.CanSet() {
+			rv.SetString(replace(path, rv.String()))
+		}
+	case reflect.Ptr:
+		if rv.IsNil() || visited[rv.Pointer()] {
+			return
+		}
+		visited[rv.Pointer()] = true
+		replaceStrings(path, rv.Elem(), replace, visited)
+	case reflect.Interface:
+		if rv.IsNil() || !rv.CanSet() {
+			return
+		}
+		// NOTE: the value held by interface is not addressable, so it is
+		// replaced by an addressable copy
+		elem := reflect.New(rv.Elem().Type()).Elem()
+		elem.Set(rv.Elem())
+		replaceStrings(path, elem, replace, visited)
+		rv.Set(elem)
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+			name := field.Name
+			if len(path) > 0 {
+				name = path + "." + name
+			}
+			replaceStrings(name, rv.Field(i), replace, visited)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			replaceStrings(fmt.Sprintf("%s[%d]", path, i), rv.Index(i), replace, visited)
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			return
+		}
+		// NOTE: visit the keys in order to keep the result stable
+		keys := rv.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		for _, key := range keys {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			elem.Set(rv.MapIndex(key))
+			replaceStrings(fmt.Sprintf("%s[%v]", path, key), elem, replace, visited)
+			rv.SetMapIndex(key, elem)
+		}
+	}
+}
@@ -3,6 +3,8 @@ package resource
 import (
 	"os"
 
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/expand"
 	"github.com/Bofry/structproto"
 )
 
@@ -10,8 +12,15 @@ const (
 	TagName = "resource"
 )
 
-func Process(baseDir string, target interface{}) error {
-	baseDir = os.ExpandEnv(baseDir)
+func Process(baseDir string, target interface{}, record common.Recorder) error {
+	path, err := expand.ExpandEnv(baseDir)
+	if err != nil {
+		return &common.FieldError{
+			Key: baseDir,
+			Err: err,
+		}
+	}
+	baseDir = path
 	if len(baseDir) > 0 {
 		// exist path
 		if _, err := os.Stat(baseDir); os.IsNotExist(err) {
@@ -28,6 +37,7 @@ func Process(baseDir string, target interface{}) error {
 	}
 
 	return prototype.Bind(&ResourceBinder{
-		BaseDir: baseDir,
+		BaseDir:  baseDir,
+		Recorder: record,
 	})
 }
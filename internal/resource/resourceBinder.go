@@ -6,6 +6,7 @@ import (
 	"path"
 	"reflect"
 
+	"github.com/Bofry/config/internal/common"
 	"github.com/Bofry/structproto"
 	"github.com/Bofry/structproto/valuebinder"
 )
@@ -18,6 +19,10 @@ var _ structproto.StructBinder = new(ResourceBinder)
 
 type ResourceBinder struct {
 	BaseDir string
+	// Recorder, if not nil, is called for each assigned field.
+	Recorder common.Recorder
+
+	errors common.FieldErrorCollector
 }
 
 func (p *ResourceBinder) Init(context *structproto.StructProtoContext) error {
@@ -27,32 +32,57 @@ func (p *ResourceBinder) Init(context *structproto.StructProtoContext) error {
 func (p *ResourceBinder) Bind(field structproto.FieldInfo, rv reflect.Value) error {
 	filename := path.Join(p.BaseDir, field.Name())
 
-	fileinfo, err := os.Stat(filename)
+	buffer, err := p.readFile(field, filename)
 	if err != nil {
-		if os.IsNotExist(err) {
-			if field.HasFlag(structproto.RequiredFlag) {
-				return err
-			}
-			return nil
-		}
-		return err
+		p.errors.Add(field.Index(), &common.FieldError{
+			Key:   filename,
+			Field: field.IDName(),
+			Err:   err,
+		})
+		return nil
+	}
+	if buffer == nil {
+		return nil
 	}
-	if fileinfo.Mode().IsRegular() {
-		buffer, err := ioutil.ReadFile(filename)
-		if err != nil {
-			return err
-		}
 
-		switch rv.Type() {
-		case typeOfByteArray:
-			rv.Set(reflect.ValueOf(buffer))
+	switch rv.Type() {
+	case typeOfByteArray:
+		rv.Set(reflect.ValueOf(buffer))
+	default:
+		err = valuebinder.BytesBinder(rv).Bind(buffer)
+		if err != nil {
+			p.errors.Add(field.Index(), &common.FieldError{
+				Key:   filename,
+				Field: field.IDName(),
+				Value: buffer,
+				Err:   err,
+			})
 			return nil
 		}
-		return valuebinder.BytesBinder(rv).Bind(buffer)
+	}
+	if p.Recorder != nil {
+		p.Recorder(field.IDName(), filename)
 	}
 	return nil
 }
 
 func (p *ResourceBinder) Deinit(context *structproto.StructProtoContext) error {
-	return nil
+	return p.errors.Err()
+}
+
+func (p *ResourceBinder) readFile(field structproto.FieldInfo, filename string) ([]byte, error) {
+	fileinfo, err := os.Stat(filename)
+	if err != nil {
+		if os.IsNotExist(err) {
+			if field.HasFlag(structproto.RequiredFlag) {
+				return nil, &structproto.MissingRequiredFieldError{Field: field.Name(), Err: err}
+			}
+			return nil, nil
+		}
+		return nil, err
+	}
+	if fileinfo.Mode().IsRegular() {
+		return ioutil.ReadFile(filename)
+	}
+	return nil, nil
 }
@@ -15,7 +15,7 @@ type config struct {
 func TestLoad_WithFromCurrentDir(t *testing.T) {
 	c := config{}
 
-	err := Process(".", &c)
+	err := Process(".", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -37,7 +37,7 @@ func TestLoad_WithFromCurrentDir(t *testing.T) {
 func TestLoad_WithEmptyStringBaseDir(t *testing.T) {
 	c := config{}
 
-	err := Process("", &c)
+	err := Process("", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -59,7 +59,7 @@ func TestLoad_WithEmptyStringBaseDir(t *testing.T) {
 func TestLoad_WithFromSpecifiedDir(t *testing.T) {
 	c := config{}
 
-	err := Process("conf", &c)
+	err := Process("conf", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -83,7 +83,7 @@ func TestLoad_WithFromEnvironmentVar(t *testing.T) {
 	os.Setenv("Environment", "dev")
 
 	c := config{}
-	err := Process("${Environment}", &c)
+	err := Process("${Environment}", &c, nil)
 	if err != nil {
 		t.Error(err)
 	}
@@ -0,0 +1,236 @@
+package validate
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	typeOfDuration = reflect.TypeOf(time.Nanosecond)
+)
+
+type (
+	Rule struct {
+		Name  string
+		Param string
+	}
+
+	checker func(rv reflect.Value, param string) error
+)
+
+var checkers = map[string]checker{
+	"nonzero":     checkNonzero,
+	"min":         checkMin,
+	"max":         checkMax,
+	"len":         checkLen,
+	"oneof":       checkOneOf,
+	"regexp":      checkRegexp,
+	"url":         checkUrl,
+	"hostport":    checkHostPort,
+	"file-exists": checkFileExists,
+}
+
+// ParseRules parses the validate tag content, e.g. "nonzero,min=0,max=15".
+// The regexp rule consumes the rest of the content, so it should be
+// placed last.
+func ParseRules(token string) ([]Rule, error) {
+	var rules []Rule
+	for len(token) > 0 {
+		var part string
+		if strings.HasPrefix(token, "regexp=") {
+			part, token = token, ""
+		} else {
+			parts := strings.SplitN(token, ",", 2)
+			part, token = parts[0], ""
+			if len(parts) == 2 {
+				token = parts[1]
+			}
+		}
+
+		part = strings.TrimSpace(part)
+		if len(part) == 0 {
+			continue
+		}
+		kv := strings.SplitN(part, "=", 2)
+		rule := Rule{Name: kv[0]}
+		if len(kv) == 2 {
+			rule.Param = kv[1]
+		}
+		if _, ok := checkers[rule.Name]; !ok {
+			return nil, fmt.Errorf("unknown validation rule '%s'", rule.Name)
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func (r Rule) Check(rv reflect.Value) error {
+	return checkers[r.Name](rv, r.Param)
+}
+
+func (r Rule) String() string {
+	if len(r.Param) > 0 {
+		return r.Name + "=" + r.Param
+	}
+	return r.Name
+}
+
+func checkNonzero(rv reflect.Value, param string) error {
+	if rv.IsZero() {
+		return fmt.Errorf("must not be zero value")
+	}
+	return nil
+}
+
+func checkMin(rv reflect.Value, param string) error {
+	return compare(rv, param, func(v, bound float64) bool { return v >= bound }, "must be greater than or equal to %s")
+}
+
+func checkMax(rv reflect.Value, param string) error {
+	return compare(rv, param, func(v, bound float64) bool { return v <= bound }, "must be less than or equal to %s")
+}
+
+func checkLen(rv reflect.Value, param string) error {
+	n, err := strconv.Atoi(param)
+	if err != nil {
+		return fmt.Errorf("invalid len parameter '%s'", param)
+	}
+	size, ok := length(rv)
+	if !ok {
+		return fmt.Errorf("len is not supported on %s", rv.Type())
+	}
+	if size != n {
+		return fmt.Errorf("length must be %d", n)
+	}
+	return nil
+}
+
+func checkOneOf(rv reflect.Value, param string) error {
+	if rv.IsZero() {
+		return nil
+	}
+	v := fmt.Sprintf("%v", rv.Interface())
+	for _, option := range strings.Fields(param) {
+		if v == option {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be one of [%s]", strings.Join(strings.Fields(param), " "))
+}
+
+func checkRegexp(rv reflect.Value, param string) error {
+	v, ok, err := stringValue(rv, "regexp")
+	if !ok {
+		return err
+	}
+	pattern, err := regexp.Compile(param)
+	if err != nil {
+		return fmt.Errorf("invalid regexp parameter: %v", err)
+	}
+	if !pattern.MatchString(v) {
+		return fmt.Errorf("must match regexp '%s'", param)
+	}
+	return nil
+}
+
+func checkUrl(rv reflect.Value, param string) error {
+	v, ok, err := stringValue(rv, "url")
+	if !ok {
+		return err
+	}
+	u, err := url.Parse(v)
+	if err != nil || len(u.Scheme) == 0 || len(u.Host) == 0 {
+		return fmt.Errorf("must be an absolute url")
+	}
+	return nil
+}
+
+func checkHostPort(rv reflect.Value, param string) error {
+	v, ok, err := stringValue(rv, "hostport")
+	if !ok {
+		return err
+	}
+	_, port, err := net.SplitHostPort(v)
+	if err != nil {
+		return fmt.Errorf("must be in form of host:port")
+	}
+	if n, err := strconv.ParseUint(port, 10, 16); err != nil || n == 0 {
+		return fmt.Errorf("must have a port number between 1 and 65535")
+	}
+	return nil
+}
+
+func checkFileExists(rv reflect.Value, param string) error {
+	v, ok, err := stringValue(rv, "file-exists")
+	if !ok {
+		return err
+	}
+	if _, err := os.Stat(v); err != nil {
+		return fmt.Errorf("file '%s' must exist", v)
+	}
+	return nil
+}
+
+// stringValue returns the string of rv, and false if the rule should be
+// skipped because rv is empty or cannot be a string.
+func stringValue(rv reflect.Value, rule string) (string, bool, error) {
+	if rv.Kind() != reflect.String {
+		return "", false, fmt.Errorf("%s is not supported on %s", rule, rv.Type())
+	}
+	v := rv.String()
+	return v, len(v) > 0, nil
+}
+
+func compare(rv reflect.Value, param string, predicate func(v, bound float64) bool, message string) error {
+	var (
+		value float64
+		bound float64
+		err   error
+	)
+
+	switch {
+	case rv.Type() == typeOfDuration:
+		var d time.Duration
+		d, err = time.ParseDuration(param)
+		value, bound = float64(rv.Int()), float64(d)
+	default:
+		bound, err = strconv.ParseFloat(param, 64)
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			value = float64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			value = float64(rv.Uint())
+		case reflect.Float32, reflect.Float64:
+			value = rv.Float()
+		default:
+			size, ok := length(rv)
+			if !ok {
+				return fmt.Errorf("cannot compare %s", rv.Type())
+			}
+			value = float64(size)
+			message = "length " + message
+		}
+	}
+	if err != nil {
+		return fmt.Errorf("invalid parameter '%s'", param)
+	}
+	if !predicate(value, bound) {
+		return fmt.Errorf(message, param)
+	}
+	return nil
+}
+
+func length(rv reflect.Value) (int, bool) {
+	switch rv.Kind() {
+	case reflect.String, reflect.Slice, reflect.Array, reflect.Map:
+		return rv.Len(), true
+	}
+	return 0, false
+}
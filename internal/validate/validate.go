@@ -0,0 +1,98 @@
+package validate
+
+import (
+	"reflect"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/reflectutil"
+	"github.com/Bofry/structproto"
+)
+
+const (
+	TagName = "validate"
+)
+
+var _ structproto.TagResolver = ValidateTagResolver
+
+// ValidateTagResolver keeps the whole tag content as the description,
+// since the rules may contain any symbol.
+func ValidateTagResolver(fieldname, token string) (*structproto.Tag, error) {
+	if len(token) > 0 {
+		return &structproto.Tag{
+			Name: fieldname,
+			Desc: token,
+		}, nil
+	}
+	return nil, nil
+}
+
+// Process checks every field of target against its validate tag, including
+// the fields of nested structs, and reports all violations.
+func Process(target interface{}) error {
+	errs := process("", reflect.ValueOf(target))
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+func process(prefix string, rv reflect.Value) common.FieldErrors {
+	rv = reflectutil.Indirect(rv)
+	if !rv.IsValid() {
+		return nil
+	}
+
+	prototype, err := structproto.Prototypify(rv, &structproto.StructProtoResolveOption{
+		TagName:     TagName,
+		TagResolver: ValidateTagResolver,
+	})
+	if err != nil {
+		return common.FieldErrors{{Field: prefix, Err: err}}
+	}
+
+	var errors common.FieldErrorCollector
+	prototype.Map(func(field structproto.FieldInfo, rv reflect.Value) error {
+		rules, err := ParseRules(field.Desc())
+		if err != nil {
+			errors.Add(field.Index(), &common.FieldError{
+				Field: prefix + field.IDName(),
+				Err:   err,
+			})
+			return nil
+		}
+		for _, rule := range rules {
+			err := rule.Check(rv)
+			if err != nil {
+				errors.Add(field.Index(), &common.FieldError{
+					Field: prefix + field.IDName(),
+					Value: rv.Interface(),
+					Err:   err,
+				})
+			}
+		}
+		return nil
+	})
+
+	// validate the nested structs
+	t := rv.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		if _, ok := field.Tag.Lookup(TagName); ok {
+			continue
+		}
+		elem := reflectutil.Indirect(rv.Field(i))
+		if elem.IsValid() && reflectutil.IsComposite(elem.Type()) {
+			for _, e := range process(prefix+field.Name+".", elem) {
+				errors.Add(i, e)
+			}
+		}
+	}
+
+	if err := errors.Err(); err != nil {
+		return err.(common.FieldErrors)
+	}
+	return nil
+}
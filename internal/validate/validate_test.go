@@ -0,0 +1,89 @@
+package validate
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/Bofry/config/internal/common"
+)
+
+type redisConfig struct {
+	Host string `validate:"nonzero,hostport"`
+	DB   int    `validate:"min=0,max=15"`
+}
+
+type config struct {
+	Redis       redisConfig
+	Environment string        `validate:"oneof=dev staging production"`
+	Endpoint    string        `validate:"url"`
+	Workspace   string        `validate:"file-exists"`
+	Name        string        `validate:"len=4,regexp=^[a-z]{1,3},?$"`
+	Timeout     time.Duration `validate:"min=1s"`
+	Tags        []string      `validate:"max=2"`
+}
+
+func TestProcess(t *testing.T) {
+	c := config{
+		Redis:       redisConfig{Host: "127.0.0.1:6379", DB: 3},
+		Environment: "staging",
+		Endpoint:    "http://localhost:8080/api",
+		Workspace:   os.TempDir(),
+		Name:        "abc,",
+		Timeout:     3 * time.Second,
+		Tags:        []string{"demo", "test"},
+	}
+
+	err := Process(&c)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestProcess_WithViolations(t *testing.T) {
+	c := config{
+		Redis:       redisConfig{Host: "127.0.0.1", DB: 16},
+		Environment: "prod",
+		Endpoint:    "localhost",
+		Workspace:   "not_exist_dir",
+		Name:        "abcd",
+		Timeout:     time.Millisecond,
+		Tags:        []string{"demo", "test", "dev"},
+	}
+
+	err := Process(&c)
+	if err == nil {
+		t.Fatalf("assert 'Process()':: expected error, got '%v'", err)
+	}
+
+	errs := err.(common.FieldErrors)
+	var expectedFields = []string{
+		"Redis.Host",
+		"Redis.DB",
+		"Environment",
+		"Endpoint",
+		"Workspace",
+		"Name",
+		"Timeout",
+		"Tags",
+	}
+	if len(errs) != len(expectedFields) {
+		t.Fatalf("assert 'FieldErrors':: expected '%v' errors, got '%v'", len(expectedFields), errs)
+	}
+	for i, expectedField := range expectedFields {
+		if errs[i].Field != expectedField {
+			t.Errorf("assert 'FieldErrors[%d].Field':: expected '%v', got '%v'", i, expectedField, errs[i].Field)
+		}
+	}
+}
+
+func TestProcess_WithUnknownRule(t *testing.T) {
+	c := struct {
+		Port int `validate:"positive"`
+	}{}
+
+	err := Process(&c)
+	if err == nil {
+		t.Errorf("assert 'Process()':: expected error, got '%v'", err)
+	}
+}
@@ -1,26 +1,191 @@
 package yaml
 
 import (
+	"errors"
+	"fmt"
 	"io/ioutil"
-	"os"
+	"reflect"
+	"strings"
 
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/expand"
+	"github.com/Bofry/config/internal/reflectutil"
 	"gopkg.in/yaml.v2"
 )
 
-func LoadFile(filepath string, target interface{}) error {
-	path := os.ExpandEnv(filepath)
+func LoadFile(filepath string, target interface{}, record common.Recorder) error {
+	path, err := expand.ExpandEnv(filepath)
+	if err != nil {
+		return &common.FieldError{
+			Key: filepath,
+			Err: err,
+		}
+	}
+
 	buffer, err := ioutil.ReadFile(path)
 	if err != nil {
 		return err
 	}
 
-	return LoadBytes(buffer, target)
+	err = LoadBytes(buffer, target, record)
+	if err != nil {
+		if errs, ok := err.(common.FieldErrors); ok {
+			for _, e := range errs {
+				e.Key = path
+			}
+		}
+		return err
+	}
+	return nil
 }
 
-func LoadBytes(buffer []byte, target interface{}) error {
+func LoadBytes(buffer []byte, target interface{}, record common.Recorder) error {
 	err := yaml.Unmarshal(buffer, target)
 	if err != nil {
-		return err
+		return toFieldErrors(buffer, target, err)
+	}
+
+	if record != nil {
+		var document interface{}
+		if yaml.Unmarshal(buffer, &document) == nil {
+			common.RecordKeys(document, record)
+		}
 	}
 	return nil
 }
+
+// A mismatch is a value of the document which can't be assigned to the
+// field of target.
+type mismatch struct {
+	field string
+	key   string
+	value interface{}
+	count int
+}
+
+func toFieldErrors(buffer []byte, target interface{}, err error) error {
+	var typeError *yaml.TypeError
+	if errors.As(err, &typeError) {
+		// yaml.TypeError reports every mismatched value of the document
+		errs := make(common.FieldErrors, len(typeError.Errors))
+		for i, message := range typeError.Errors {
+			errs[i] = &common.FieldError{
+				Err: errors.New(message),
+			}
+		}
+
+		// NOTE: the mismatched values are reported in document order
+		// without their fields, so the document is decoded field by field
+		// again to resolve them. The fields are left empty if they can't
+		// be resolved.
+		var document yaml.MapSlice
+		if yaml.Unmarshal(buffer, &document) != nil {
+			return errs
+		}
+		var (
+			mismatches []*mismatch
+			count      int
+		)
+		findMismatches(document, reflect.TypeOf(target), "", nil, &mismatches)
+		for _, m := range mismatches {
+			count += m.count
+		}
+		if count != len(errs) {
+			return errs
+		}
+
+		i := 0
+		for _, m := range mismatches {
+			for n := 0; n < m.count; n, i = n+1, i+1 {
+				errs[i].Key = m.key
+				errs[i].Field = m.field
+				errs[i].Value = m.value
+			}
+		}
+		return errs
+	}
+	return common.FieldErrors{
+		&common.FieldError{
+			Err: err,
+		},
+	}
+}
+
+func findMismatches(document yaml.MapSlice, t reflect.Type, key string, path []string, mismatches *[]*mismatch) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct {
+		return
+	}
+
+	fields := make(map[string]reflect.StructField)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if len(field.PkgPath) > 0 {
+			continue
+		}
+		name := strings.SplitN(field.Tag.Get("yaml"), ",", 2)[0]
+		if name == "-" {
+			continue
+		}
+		if len(name) == 0 {
+			name = strings.ToLower(field.Name)
+		}
+		fields[name] = field
+	}
+
+	for _, item := range document {
+		name := fmt.Sprint(item.Key)
+		field, ok := fields[name]
+		if !ok {
+			continue
+		}
+
+		itemKey := name
+		if len(key) > 0 {
+			itemKey = key + "." + name
+		}
+		itemPath := append(path[:len(path):len(path)], field.Name)
+
+		fieldType := field.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		if inner, ok := item.Value.(yaml.MapSlice); ok && reflectutil.IsComposite(fieldType) {
+			findMismatches(inner, fieldType, itemKey, itemPath, mismatches)
+			continue
+		}
+
+		count := countMismatches(item.Value, field.Type)
+		if count == 0 {
+			continue
+		}
+		m := &mismatch{
+			field: strings.Join(itemPath, "."),
+			key:   itemKey,
+			count: count,
+		}
+		switch item.Value.(type) {
+		case yaml.MapSlice, []interface{}:
+		default:
+			m.value = item.Value
+		}
+		*mismatches = append(*mismatches, m)
+	}
+}
+
+// countMismatches decodes value into a new value of type t, and returns
+// the number of the mismatched values reported.
+func countMismatches(value interface{}, t reflect.Type) int {
+	buffer, err := yaml.Marshal(value)
+	if err != nil {
+		return 0
+	}
+
+	var typeError *yaml.TypeError
+	if errors.As(yaml.Unmarshal(buffer, reflect.New(t).Interface()), &typeError) {
+		return len(typeError.Errors)
+	}
+	return 0
+}
@@ -3,9 +3,9 @@ package json
 import "github.com/Bofry/config/internal/json"
 
 func LoadFile(filepath string, target interface{}) error {
-	return json.LoadFile(filepath, target)
+	return json.LoadFile(filepath, target, nil)
 }
 
 func LoadBytes(buffer []byte, target interface{}) error {
-	return json.LoadBytes(buffer, target)
+	return json.LoadBytes(buffer, target, nil)
 }
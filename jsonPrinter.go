@@ -0,0 +1,37 @@
+package config
+
+import (
+	"encoding/json"
+	"io"
+)
+
+var _ Printer = new(JsonPrinter)
+
+// JsonPrinter prints the target in JSON by its json tags. The fields are
+// printed in declaration order, and the secret fields are masked. The
+// characters like '<' and '&' are printed as they are, e.g. in URLs.
+type JsonPrinter struct {
+	writer io.Writer
+	prefix string
+	indent string
+}
+
+func NewJsonPrinter(writer io.Writer) *JsonPrinter {
+	return &JsonPrinter{
+		writer: writer,
+	}
+}
+
+// SetIndent makes the printer indent the output like json.MarshalIndent().
+func (p *JsonPrinter) SetIndent(prefix, indent string) *JsonPrinter {
+	p.prefix = prefix
+	p.indent = indent
+	return p
+}
+
+func (p *JsonPrinter) Print(target interface{}) error {
+	encoder := json.NewEncoder(p.writer)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent(p.prefix, p.indent)
+	return encoder.Encode(MaskSecrets(target))
+}
@@ -0,0 +1,178 @@
+package config
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/Bofry/config/internal/env"
+	"github.com/Bofry/config/internal/resource"
+	"github.com/Bofry/structproto"
+	"gopkg.in/yaml.v2"
+)
+
+var _ Printer = new(ManifestPrinter)
+
+// ManifestPrinter prints the Kubernetes ConfigMap of the ordinary fields
+// and the Secret of the secret fields. The fields are keyed by the names
+// in their env tags, to be loaded by LoadEnvironmentVariables() through
+// envFrom, or by the file names in their resource tags, to be loaded by
+// LoadResource() from the mounted volume. The values of the Secret are
+// empty unless IncludeSecretValues() is called.
+type ManifestPrinter struct {
+	writer       io.Writer
+	name         string
+	namespace    string
+	prefix       string
+	secretValues bool
+}
+
+type manifestMetadata struct {
+	Name      string `yaml:"name"`
+	Namespace string `yaml:"namespace,omitempty"`
+}
+
+type manifest struct {
+	APIVersion string            `yaml:"apiVersion"`
+	Kind       string            `yaml:"kind"`
+	Metadata   manifestMetadata  `yaml:"metadata"`
+	Type       string            `yaml:"type,omitempty"`
+	Data       map[string]string `yaml:"data,omitempty"`
+	BinaryData map[string]string `yaml:"binaryData,omitempty"`
+}
+
+// NewManifestPrinter creates a ManifestPrinter which names both the
+// ConfigMap and the Secret by name.
+func NewManifestPrinter(writer io.Writer, name string) *ManifestPrinter {
+	return &ManifestPrinter{
+		writer: writer,
+		name:   name,
+	}
+}
+
+// SetNamespace sets the namespace of the manifests.
+func (p *ManifestPrinter) SetNamespace(namespace string) *ManifestPrinter {
+	p.namespace = namespace
+	return p
+}
+
+// SetPrefix sets the prefix of the variable names, like the prefix given
+// to LoadEnvironmentVariables().
+func (p *ManifestPrinter) SetPrefix(prefix string) *ManifestPrinter {
+	p.prefix = prefix
+	return p
+}
+
+// IncludeSecretValues makes the printer write the values of the secret
+// fields into the Secret.
+func (p *ManifestPrinter) IncludeSecretValues() *ManifestPrinter {
+	p.secretValues = true
+	return p
+}
+
+func (p *ManifestPrinter) Print(target interface{}) error {
+	metadata := manifestMetadata{
+		Name:      p.name,
+		Namespace: p.namespace,
+	}
+	configMap := &manifest{
+		APIVersion: "v1",
+		Kind:       "ConfigMap",
+		Metadata:   metadata,
+		Data:       make(map[string]string),
+		BinaryData: make(map[string]string),
+	}
+	secret := &manifest{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Metadata:   metadata,
+		Type:       "Opaque",
+		Data:       make(map[string]string),
+	}
+
+	prefix := p.prefix
+	if len(prefix) > 0 {
+		prefix += "_"
+	}
+	sources := []struct {
+		tagName  string
+		resolver structproto.TagResolver
+		prefix   string
+	}{
+		{env.TagName, nil, prefix},
+		{resource.TagName, resource.ResourceTagResolver, ""},
+	}
+	for _, source := range sources {
+		prototype, err := structproto.Prototypify(target, &structproto.StructProtoResolveOption{
+			TagName:     source.tagName,
+			TagResolver: source.resolver,
+		})
+		if err != nil {
+			return err
+		}
+
+		err = prototype.Map(func(field structproto.FieldInfo, rv reflect.Value) error {
+			key := source.prefix + field.Name()
+			value, binary := formatManifestValue(rv)
+
+			if isSecret(findStructField(target, field)) {
+				if !p.secretValues {
+					value = ""
+				}
+				secret.Data[key] = base64.StdEncoding.EncodeToString([]byte(value))
+				return nil
+			}
+			if binary {
+				configMap.BinaryData[key] = base64.StdEncoding.EncodeToString([]byte(value))
+				return nil
+			}
+			configMap.Data[key] = value
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	var documents []*manifest
+	if len(configMap.Data) > 0 || len(configMap.BinaryData) > 0 {
+		documents = append(documents, configMap)
+	}
+	if len(secret.Data) > 0 {
+		documents = append(documents, secret)
+	}
+	for i, document := range documents {
+		buffer, err := yaml.Marshal(document)
+		if err != nil {
+			return err
+		}
+		if i > 0 {
+			fmt.Fprintln(p.writer, "---")
+		}
+		_, err = p.writer.Write(buffer)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// formatManifestValue formats rv like EnvPrinter, and reports whether it
+// is binary content.
+func formatManifestValue(rv reflect.Value) (string, bool) {
+	if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+		return string(rv.Bytes()), true
+	}
+	return formatEnvValue(rv), false
+}
+
+// findStructField returns the path of the top-level field of target
+// described by field.
+func findStructField(target interface{}, field structproto.FieldInfo) []reflect.StructField {
+	t := reflect.TypeOf(target)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return []reflect.StructField{t.Field(field.Index())}
+}
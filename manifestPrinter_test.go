@@ -0,0 +1,75 @@
+package config
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestManifestPrinter(t *testing.T) {
+	conf := struct {
+		RedisHost     string   `env:"REDIS_HOST"`
+		RedisPassword string   `env:"*REDIS_PASSWORD"   secret:"true"`
+		RedisDB       int      `env:"REDIS_DB"`
+		Tags          []string `env:"TAG"`
+		Version       string   `resource:".VERSION"`
+		Certificate   []byte   `resource:"tls.key"    secret:"true"`
+		Logo          []byte   `resource:"logo.png"`
+		Workspace     string   `yaml:"workspace"`
+	}{
+		RedisHost:     "127.0.0.1:6379",
+		RedisPassword: "1234",
+		RedisDB:       3,
+		Tags:          []string{"demo", "test"},
+		Version:       "v1.0.2",
+		Certificate:   []byte("key"),
+		Logo:          []byte{0x89, 0x50},
+		Workspace:     "demo",
+	}
+
+	var buffer bytes.Buffer
+	err := NewManifestPrinter(&buffer, "demo").
+		SetNamespace("staging").
+		Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: demo
+  namespace: staging
+data:
+  .VERSION: v1.0.2
+  REDIS_DB: "3"
+  REDIS_HOST: 127.0.0.1:6379
+  TAG: demo,test
+binaryData:
+  logo.png: iVA=
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: demo
+  namespace: staging
+type: Opaque
+data:
+  REDIS_PASSWORD: ""
+  tls.key: ""
+`
+	if buffer.String() != expected {
+		t.Errorf("assert 'ManifestPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+
+	buffer.Reset()
+	err = NewManifestPrinter(&buffer, "demo").
+		SetPrefix("K8S").
+		IncludeSecretValues().
+		Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Contains(buffer.Bytes(), []byte("K8S_REDIS_PASSWORD: MTIzNA==")) {
+		t.Errorf("assert 'ManifestPrinter.Print()':: expected '%v', got '%v'", "K8S_REDIS_PASSWORD: MTIzNA==", buffer.String())
+	}
+}
@@ -0,0 +1,156 @@
+package config
+
+import (
+	"path"
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/env"
+	"github.com/Bofry/config/internal/flag"
+	"github.com/Bofry/config/internal/reflectutil"
+	"github.com/Bofry/config/internal/resource"
+	"github.com/Bofry/structproto"
+	"github.com/Bofry/structproto/tagresolver"
+)
+
+// An Origin describes which source assigned the value of a field.
+type Origin struct {
+	Source   string      // the source name, e.g. SourceEnv, SourceYaml
+	Location string      // the file path of the source, if any
+	Key      string      // the variable name, argument name, or key in the file
+	Value    interface{} // the assigned value
+	Previous interface{} // the value overridden by the source
+}
+
+func (o *Origin) String() string {
+	var sb strings.Builder
+	sb.WriteString(o.Source)
+	if len(o.Key) > 0 {
+		sb.WriteString(" ")
+		sb.WriteString(o.Key)
+	}
+	if len(o.Location) > 0 && o.Location != o.Key {
+		sb.WriteString(" @")
+		sb.WriteString(o.Location)
+	}
+	return sb.String()
+}
+
+type (
+	// keyNaming resolves the name of the specified field in a source.
+	keyNaming func(fields []reflect.StructField) string
+)
+
+func envNaming(prefix string) keyNaming {
+	if len(prefix) > 0 {
+		prefix += "_"
+	}
+	return func(fields []reflect.StructField) string {
+		name := resolveTagName(fields, env.TagName, tagresolver.StdTagResolver)
+		if len(name) > 0 {
+			return prefix + name
+		}
+		return ""
+	}
+}
+
+func tagNaming(tagName string) keyNaming {
+	return func(fields []reflect.StructField) string {
+		return resolveTagName(fields, tagName, tagresolver.StdTagResolver)
+	}
+}
+
+func argNaming() keyNaming {
+	return func(fields []reflect.StructField) string {
+		return resolveTagName(fields, flag.TagName, tagresolver.StdTagResolver)
+	}
+}
+
+func resourceNaming(baseDir string) keyNaming {
+	return func(fields []reflect.StructField) string {
+		name := resolveTagName(fields, resource.TagName, resource.ResourceTagResolver)
+		if len(name) > 0 {
+			return path.Join(baseDir, name)
+		}
+		return ""
+	}
+}
+
+func yamlNaming() keyNaming {
+	return func(fields []reflect.StructField) string {
+		return resolveKeyPath(fields, "yaml", strings.ToLower)
+	}
+}
+
+func jsonNaming() keyNaming {
+	return func(fields []reflect.StructField) string {
+		return resolveKeyPath(fields, "json", func(name string) string { return name })
+	}
+}
+
+// resolveTagName resolves the name of the field for the
+// sources which don't support nested structs.
+func resolveTagName(fields []reflect.StructField, tagName string, resolver structproto.TagResolver) string {
+	if len(fields) != 1 {
+		return ""
+	}
+
+	field := fields[0]
+	tag, err := resolver(field.Name, field.Tag.Get(tagName))
+	if err != nil || tag == nil {
+		return ""
+	}
+	return tag.Name
+}
+
+func resolveKeyPath(fields []reflect.StructField, tagName string, defaultName func(string) string) string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		name := strings.SplitN(field.Tag.Get(tagName), ",", 2)[0]
+		if name == "-" {
+			return ""
+		}
+		if len(name) == 0 {
+			name = defaultName(field.Name)
+		}
+		names[i] = name
+	}
+	return strings.Join(names, ".")
+}
+
+// assignments collects the fields and the keys which the loaders of a
+// step report by common.Recorder.
+type assignments struct {
+	fields map[string]bool
+	keys   map[string]bool
+}
+
+func newAssignments() *assignments {
+	return &assignments{
+		fields: make(map[string]bool),
+		keys:   make(map[string]bool),
+	}
+}
+
+func (a *assignments) record(field string, key string) {
+	if len(field) > 0 {
+		a.fields[field] = true
+	}
+	if len(key) > 0 {
+		a.keys[key] = true
+	}
+}
+
+// contains reports whether the field was assigned by step. The loaders
+// which don't know the fields, like yaml and json, report the keys only,
+// which are matched by the naming of step.
+func (a *assignments) contains(step *loadStep, path []reflect.StructField) bool {
+	if a.fields[reflectutil.PathName(path)] {
+		return true
+	}
+	if step.naming == nil || len(a.keys) == 0 {
+		return false
+	}
+	key := step.naming(path)
+	return len(key) > 0 && a.keys[key]
+}
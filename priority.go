@@ -0,0 +1,57 @@
+package config
+
+// Priority orders the loaded sources. The sources of higher priority
+// override the ones of lower priority regardless of the calling order; the
+// sources of the same priority are applied in the calling order.
+type Priority int
+
+const (
+	PriorityDefault Priority = 0
+	PriorityFile    Priority = 100
+	PriorityDotEnv  Priority = 200
+	PriorityEnv     Priority = 300
+	PriorityArg     Priority = 400
+)
+
+// WithPriority sets the priority of the source loaded by the next Load*
+// call. Without it, a source has the priority of the source loaded before
+// it, or the priority of its kind after UseSourcePriorities().
+func (service *ConfigurationService) WithPriority(priority Priority) *ConfigurationService {
+	service.pendingPriority = &priority
+	return service
+}
+
+// UseSourcePriorities gives the sources loaded without WithPriority() the
+// priority of their kind: PriorityFile for the json, yaml, resource, and
+// custom sources, PriorityDotEnv, PriorityEnv, and PriorityArg.
+func (service *ConfigurationService) UseSourcePriorities() *ConfigurationService {
+	service.sourcePriorities = true
+	return service
+}
+
+func (service *ConfigurationService) nextPriority(step *loadStep) Priority {
+	switch {
+	case service.pendingPriority != nil:
+		priority := *service.pendingPriority
+		service.pendingPriority = nil
+		return priority
+	case service.sourcePriorities:
+		return sourcePriority(step.source)
+	default:
+		return service.lastPriority
+	}
+}
+
+func sourcePriority(source string) Priority {
+	switch source {
+	case SourceDefault:
+		return PriorityDefault
+	case SourceDotEnv:
+		return PriorityDotEnv
+	case SourceEnv:
+		return PriorityEnv
+	case SourceArg:
+		return PriorityArg
+	}
+	return PriorityFile
+}
@@ -0,0 +1,55 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestConfigurationService_WithPriority(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_DB", "32")
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		WithPriority(PriorityEnv).LoadEnvironmentVariables("").
+		WithPriority(PriorityFile).LoadYamlBytes([]byte("redisDB: 3\nworkspace: demo"))
+
+	if conf.RedisDB != 32 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 32, conf.RedisDB)
+	}
+	var expectedWorkspace = "demo"
+	if conf.Workspace != expectedWorkspace {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", expectedWorkspace, conf.Workspace)
+	}
+	history := service.History("RedisDB")
+	if len(history) != 2 || history[0].Source != SourceYaml || history[1].Source != SourceEnv {
+		t.Errorf("assert 'ConfigurationService.History()':: expected '%v', got '%v'", "[yaml env]", history)
+	}
+
+	// the sources loaded without priority follow the previous one
+	service.LoadYamlBytes([]byte("redisDB: 4"))
+	if conf.RedisDB != 32 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 32, conf.RedisDB)
+	}
+}
+
+func TestConfigurationService_UseSourcePriorities(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_DB", "32")
+
+	conf := DummyConfig{}
+
+	NewConfigurationService(&conf).
+		UseSourcePriorities().
+		LoadEnvironmentVariables("").
+		LoadYamlBytes([]byte("redisDB: 3\nredisPoolSize: 10")).
+		WithPriority(PriorityArg).LoadYamlBytes([]byte("redisPoolSize: 20"))
+
+	if conf.RedisDB != 32 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 32, conf.RedisDB)
+	}
+	if conf.RedisPoolSize != 20 {
+		t.Errorf("assert 'DummyConfig.RedisPoolSize':: expected '%v', got '%v'", 20, conf.RedisPoolSize)
+	}
+}
@@ -0,0 +1,167 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/env"
+	"github.com/Bofry/config/internal/flag"
+	"github.com/Bofry/config/internal/reflectutil"
+	"github.com/Bofry/config/internal/resource"
+	"github.com/Bofry/structproto"
+	"github.com/Bofry/structproto/tagresolver"
+)
+
+const (
+	RequiredTagName = "required"
+)
+
+var (
+	ErrMissingRequiredValue = errors.New("missing required value")
+)
+
+// isRequired reports whether the field is marked as required by the
+// required tag, or by the required flag of the env, arg, or resource tag,
+// or of the tags of the specified key/value sources.
+func isRequired(field reflect.StructField, tagNames ...string) bool {
+	if v, ok := field.Tag.Lookup(RequiredTagName); ok {
+		return v == "true"
+	}
+
+	resolvers := []struct {
+		tagName  string
+		resolver structproto.TagResolver
+	}{
+		{env.TagName, tagresolver.StdTagResolver},
+		{flag.TagName, tagresolver.StdTagResolver},
+		{resource.TagName, resource.ResourceTagResolver},
+	}
+	for _, tagName := range tagNames {
+		resolvers = append(resolvers, struct {
+			tagName  string
+			resolver structproto.TagResolver
+		}{tagName, tagresolver.StdTagResolver})
+	}
+	for _, r := range resolvers {
+		tag, err := r.resolver(field.Name, field.Tag.Get(r.tagName))
+		if err != nil || tag == nil {
+			continue
+		}
+		for _, flag := range tag.Flags {
+			if flag == structproto.RequiredFlag {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func isMissingRequiredFieldError(err *FieldError) bool {
+	var missingRequiredFieldError *structproto.MissingRequiredFieldError
+	return errors.As(err.Err, &missingRequiredFieldError)
+}
+
+// requireFields drops the missing required field errors reported by the
+// binders of a step, so Require() is the only one deciding the required
+// fields. In panicking mode, the fields are checked at once against the
+// merged target, so a value provided by the previous sources satisfies
+// the requirement, but one provided by the following sources doesn't.
+func (service *ConfigurationService) requireFields(source string, err error, target reflect.Value) error {
+	if err == nil {
+		return nil
+	}
+
+	var (
+		errs    []*FieldError
+		missing = make(map[string]bool)
+	)
+	for _, e := range makeFieldErrors(source, err) {
+		if isMissingRequiredFieldError(e) {
+			missing[e.Field] = true
+			continue
+		}
+		errs = append(errs, e)
+	}
+	if len(missing) == 0 {
+		return err
+	}
+
+	if !service.collectErrors {
+		for _, e := range service.checkRequired(target) {
+			// NOTE: the errors without field are checked against all fields
+			if missing[e.Field] || missing[""] {
+				errs = append(errs, e)
+			}
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return common.FieldErrors(errs)
+}
+
+func (service *ConfigurationService) checkRequired(target reflect.Value) []*FieldError {
+	var (
+		errs     []*FieldError
+		tagNames []string
+	)
+	for _, step := range service.steps {
+		if len(step.tagName) > 0 {
+			tagNames = append(tagNames, step.tagName)
+		}
+	}
+	reflectutil.WalkLeaves(target, func(path []reflect.StructField, rv reflect.Value) {
+		if !isRequired(path[len(path)-1], tagNames...) {
+			return
+		}
+
+		field := reflectutil.PathName(path)
+		if !rv.IsZero() || len(service.origins[field]) > 0 {
+			return
+		}
+
+		err := ErrMissingRequiredValue
+		if candidates := service.candidateKeys(path); len(candidates) > 0 {
+			err = fmt.Errorf("%w, expected from %s", ErrMissingRequiredValue, strings.Join(candidates, ", "))
+		}
+		errs = append(errs, &FieldError{
+			Source: RequiredTagName,
+			Field:  field,
+			Err:    err,
+		})
+	})
+	return errs
+}
+
+// candidateKeys lists the keys of the loaded sources which could provide
+// the value of the field.
+func (service *ConfigurationService) candidateKeys(path []reflect.StructField) []string {
+	var (
+		candidates []string
+		visited    = make(map[string]bool)
+	)
+	for _, step := range service.steps {
+		if step.naming == nil {
+			continue
+		}
+		key := step.naming(path)
+		if len(key) == 0 {
+			continue
+		}
+
+		origin := &Origin{
+			Source:   step.source,
+			Location: step.location,
+			Key:      key,
+		}
+		candidate := origin.String()
+		if !visited[candidate] {
+			visited[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+	return candidates
+}
@@ -0,0 +1,127 @@
+package config
+
+import (
+	"reflect"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+const (
+	SecretTagName = "secret"
+	SecretMask    = "******"
+)
+
+// isSecret reports whether the field or any struct containing it is
+// tagged with `secret:"true"`.
+func isSecret(path []reflect.StructField) bool {
+	for _, field := range path {
+		if field.Tag.Get(SecretTagName) == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// MaskSecrets returns a copy of target whose secret fields are masked. The
+// non-empty strings are replaced with SecretMask, and the secret fields of
+// other types are cleared, including the ones of the structs in slices,
+// arrays, and map values. The target itself is not changed.
+func MaskSecrets(target interface{}) interface{} {
+	rv := reflect.ValueOf(target)
+	if inner := reflectutil.Indirect(rv); !inner.IsValid() || inner.Kind() != reflect.Struct {
+		return target
+	}
+
+	replica := reflectutil.DeepCopy(rv)
+	if replica.Kind() != reflect.Ptr {
+		// NOTE: make the copy addressable to mask its fields
+		container := reflect.New(replica.Type())
+		container.Elem().Set(replica)
+		maskSecrets(container, make(map[uintptr]bool))
+		return container.Elem().Interface()
+	}
+	maskSecrets(replica, make(map[uintptr]bool))
+	return replica.Interface()
+}
+
+// maskSecrets masks the secret fields reachable from rv through the
+// exported struct fields, pointers, interfaces, slices, arrays, and map
+// values.
+func maskSecrets(rv reflect.Value, visited map[uintptr]bool) {
+	switch rv.Kind() {
+	case reflect.Ptr:
+		if rv.IsNil() || visited[rv.Pointer()] {
+			return
+		}
+		visited[rv.Pointer()] = true
+		maskSecrets(rv.Elem(), visited)
+	case reflect.Interface:
+		if rv.IsNil() || !rv.CanSet() {
+			return
+		}
+		// NOTE: the value held by interface is not addressable, so it is
+		// replaced by an addressable copy
+		elem := reflect.New(rv.Elem().Type()).Elem()
+		elem.Set(rv.Elem())
+		maskSecrets(elem, visited)
+		rv.Set(elem)
+	case reflect.Struct:
+		t := rv.Type()
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if len(field.PkgPath) > 0 {
+				continue
+			}
+			if isSecret([]reflect.StructField{field}) {
+				if rv.Field(i).CanSet() {
+					maskValue(rv.Field(i))
+				}
+				continue
+			}
+			maskSecrets(rv.Field(i), visited)
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < rv.Len(); i++ {
+			maskSecrets(rv.Index(i), visited)
+		}
+	case reflect.Map:
+		if rv.IsNil() {
+			return
+		}
+		iter := rv.MapRange()
+		for iter.Next() {
+			elem := reflect.New(rv.Type().Elem()).Elem()
+			elem.Set(iter.Value())
+			maskSecrets(elem, visited)
+			rv.SetMapIndex(iter.Key(), elem)
+		}
+	}
+}
+
+func maskValue(rv reflect.Value) {
+	switch rv.Kind() {
+	case reflect.String:
+		if rv.Len() > 0 {
+			rv.SetString(SecretMask)
+		}
+	case reflect.Slice, reflect.Array:
+		if rv.Type().Elem().Kind() == reflect.String {
+			for i := 0; i < rv.Len(); i++ {
+				maskValue(rv.Index(i))
+			}
+			return
+		}
+		rv.Set(reflect.Zero(rv.Type()))
+	default:
+		rv.Set(reflect.Zero(rv.Type()))
+	}
+}
+
+// formatSecret formats v for the printers, masking it if the field is
+// secret.
+func formatSecret(path []reflect.StructField, v interface{}) string {
+	if isSecret(path) && v != nil && !reflect.ValueOf(v).IsZero() {
+		return SecretMask
+	}
+	return formatValue(v)
+}
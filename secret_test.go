@@ -0,0 +1,135 @@
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+)
+
+type secretConfig struct {
+	RedisHost     string   `yaml:"redisHost"`
+	RedisPassword string   `yaml:"redisPassword"   secret:"true"`
+	RedisDB       int      `yaml:"redisDB"         secret:"true"`
+	Tokens        []string `yaml:"tokens"          secret:"true"`
+}
+
+type printableSecretConfig secretConfig
+
+func (c *printableSecretConfig) Output(writer io.Writer) error {
+	fmt.Fprintf(writer, "RedisPassword: %v\n", c.RedisPassword)
+	return nil
+}
+
+func TestCommonPrinter_WithSecret(t *testing.T) {
+	conf := secretConfig{
+		RedisHost:     "127.0.0.1:6379",
+		RedisPassword: "1234",
+		RedisDB:       3,
+		Tokens:        []string{"abcd", ""},
+	}
+
+	var buffer bytes.Buffer
+	err := NewCommonPrinter(&buffer).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = "&{RedisHost:127.0.0.1:6379 RedisPassword:****** RedisDB:0 Tokens:[****** ]}\n"
+	if buffer.String() != expected {
+		t.Errorf("assert 'CommonPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+	if conf.RedisPassword != "1234" {
+		t.Errorf("assert 'RedisPassword':: expected '%v', got '%v'", "1234", conf.RedisPassword)
+	}
+	if conf.Tokens[0] != "abcd" {
+		t.Errorf("assert 'Tokens[0]':: expected '%v', got '%v'", "abcd", conf.Tokens[0])
+	}
+}
+
+func TestArbitraryPrinter_WithSecret(t *testing.T) {
+	conf := printableSecretConfig{
+		RedisPassword: "1234",
+	}
+
+	var buffer bytes.Buffer
+	err := NewArbitraryPrinter(&buffer, NonePrinter{}).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = "RedisPassword: ******\n"
+	if buffer.String() != expected {
+		t.Errorf("assert 'ArbitraryPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+}
+
+func TestExplainPrinter_WithSecret(t *testing.T) {
+	conf := secretConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisPassword: 1234")).
+		LoadYamlBytes([]byte("redisPassword: abcd"))
+
+	var buffer bytes.Buffer
+	err := NewExplainPrinter(&buffer, service).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buffer.String(), "1234") || strings.Contains(buffer.String(), "abcd") {
+		t.Errorf("assert 'ExplainPrinter.Print()':: expected masked secrets, got '%v'", buffer.String())
+	}
+}
+
+type secretServer struct {
+	Host     string `json:"host"`
+	Password string `json:"password"   secret:"true"`
+}
+
+func TestJsonPrinter_WithSecretInCollections(t *testing.T) {
+	conf := struct {
+		Servers []secretServer          `json:"servers"`
+		ByName  map[string]secretServer `json:"byName"`
+		Any     interface{}             `json:"any"`
+	}{
+		Servers: []secretServer{{Host: "a", Password: "hunter2"}},
+		ByName:  map[string]secretServer{"b": {Host: "b", Password: "pw2"}},
+		Any:     &secretServer{Host: "c", Password: "pw3"},
+	}
+
+	var buffer bytes.Buffer
+	err := NewJsonPrinter(&buffer).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = `{"servers":[{"host":"a","password":"******"}],"byName":{"b":{"host":"b","password":"******"}},"any":{"host":"c","password":"******"}}` + "\n"
+	if buffer.String() != expected {
+		t.Errorf("assert 'JsonPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+	if conf.Servers[0].Password != "hunter2" || conf.ByName["b"].Password != "pw2" {
+		t.Errorf("assert 'conf':: expected the target unchanged, got '%+v'", conf)
+	}
+}
+
+func TestCommonPrinter_WithSecretInCollections(t *testing.T) {
+	conf := struct {
+		Servers [1]secretServer
+		ByName  map[string]secretServer
+	}{
+		Servers: [1]secretServer{{Host: "a", Password: "hunter2"}},
+		ByName:  map[string]secretServer{"b": {Host: "b", Password: "pw2"}},
+	}
+
+	var buffer bytes.Buffer
+	err := NewCommonPrinter(&buffer).Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = "&{Servers:[{Host:a Password:******}] ByName:map[b:{Host:b Password:******}]}\n"
+	if buffer.String() != expected {
+		t.Errorf("assert 'CommonPrinter.Print()':: expected '%v', got '%v'", expected, buffer.String())
+	}
+}
@@ -0,0 +1,68 @@
+package config
+
+import (
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// ReloadOnSignal calls Reload() when the process receives any of the
+// signals, SIGHUP if none is specified. The signals arrived within the
+// debounce duration trigger only one reload. Call the returned function to
+// stop listening.
+func (service *ConfigurationService) ReloadOnSignal(debounce time.Duration, signals ...os.Signal) (stop func()) {
+	if len(signals) == 0 {
+		signals = []os.Signal{syscall.SIGHUP}
+	}
+
+	c := make(chan os.Signal, 1)
+	signal.Notify(c, signals...)
+
+	stopReload := service.reloadOn(c, debounce)
+	return func() {
+		signal.Stop(c)
+		stopReload()
+	}
+}
+
+func (service *ConfigurationService) reloadOn(c <-chan os.Signal, debounce time.Duration) (stop func()) {
+	done := make(chan struct{})
+	go func() {
+		var (
+			timer   *time.Timer
+			trigger <-chan time.Time
+		)
+		for {
+			select {
+			case <-done:
+				if timer != nil {
+					timer.Stop()
+				}
+				return
+			case <-c:
+				if timer == nil {
+					timer = time.NewTimer(debounce)
+				} else {
+					if !timer.Stop() {
+						select {
+						case <-timer.C:
+						default:
+						}
+					}
+					timer.Reset(debounce)
+				}
+				trigger = timer.C
+			case <-trigger:
+				trigger = nil
+				service.Reload()
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { close(done) })
+	}
+}
@@ -0,0 +1,39 @@
+package config
+
+import (
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestConfigurationService_ReloadOnSignal(t *testing.T) {
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisDB: 3"))
+
+	var reloaded int32
+	service.Subscribe(func(target interface{}) {
+		atomic.AddInt32(&reloaded, 1)
+	})
+
+	c := make(chan os.Signal)
+	stop := service.reloadOn(c, 50*time.Millisecond)
+	defer stop()
+
+	for i := 0; i < 5; i++ {
+		c <- syscall.SIGHUP
+	}
+	time.Sleep(200 * time.Millisecond)
+	if n := atomic.LoadInt32(&reloaded); n != 1 {
+		t.Errorf("assert 'reloaded':: expected '%v', got '%v'", 1, n)
+	}
+
+	c <- syscall.SIGHUP
+	time.Sleep(200 * time.Millisecond)
+	if n := atomic.LoadInt32(&reloaded); n != 2 {
+		t.Errorf("assert 'reloaded':: expected '%v', got '%v'", 2, n)
+	}
+}
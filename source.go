@@ -0,0 +1,197 @@
+package config
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/Bofry/config/internal/common"
+	"github.com/Bofry/config/internal/keyvalue"
+)
+
+type (
+	// A Source provides configuration values. Besides Name(), which is
+	// used as the source of the provenance and the errors, a Source must
+	// implement either TargetLoader or KeyValueLoader. It can implement
+	// FileSource or WatchableSource to be watched by Watch().
+	Source interface {
+		Name() string
+	}
+
+	// TargetLoader loads the values into the target directly, like the
+	// yaml and json sources.
+	TargetLoader interface {
+		Load(target interface{}) error
+	}
+
+	// KeyValueLoader produces key/value pairs, like the env source. The
+	// values are assigned to the fields by the struct tag named after the
+	// source, e.g. `consul:"REDIS_HOST"`; the field is required if the
+	// tag has the required flag, e.g. `consul:"*REDIS_HOST"`.
+	KeyValueLoader interface {
+		LoadValues() (map[string]string, error)
+	}
+
+	// FileSource lists the files which Watch() polls.
+	FileSource interface {
+		Files() []string
+	}
+
+	// WatchableSource calls changed when the source changes, then Watch()
+	// reloads. The returned function stops watching.
+	WatchableSource interface {
+		Watch(changed func()) (stop func())
+	}
+
+	// SourceFactory creates a Source from the location given to
+	// LoadFrom(), e.g. a file path or an URL.
+	SourceFactory func(location string) (Source, error)
+)
+
+var (
+	sourceFactories      = make(map[string]SourceFactory)
+	sourceFactoriesMutex sync.RWMutex
+)
+
+// builtinSource adapts the built-in Load* methods to the registry.
+type builtinSource struct {
+	name string
+	load func(service *ConfigurationService)
+}
+
+func (s *builtinSource) Name() string {
+	return s.name
+}
+
+func init() {
+	builtins := map[string]func(service *ConfigurationService, location string){
+		SourceEnv: func(service *ConfigurationService, location string) {
+			service.LoadEnvironmentVariables(location)
+		},
+		SourceDotEnv: func(service *ConfigurationService, location string) {
+			if len(location) == 0 {
+				service.LoadDotEnv()
+				return
+			}
+			service.LoadDotEnvFile(location)
+		},
+		SourceArg: func(service *ConfigurationService, location string) {
+			service.LoadCommandArguments()
+		},
+		SourceJson: func(service *ConfigurationService, location string) {
+			service.LoadJsonFile(location)
+		},
+		SourceYaml: func(service *ConfigurationService, location string) {
+			service.LoadYamlFile(location)
+		},
+		SourceResource: func(service *ConfigurationService, location string) {
+			service.LoadResource(location)
+		},
+	}
+	for name, load := range builtins {
+		name, load := name, load
+		RegisterSource(name, func(location string) (Source, error) {
+			return &builtinSource{
+				name: name,
+				load: func(service *ConfigurationService) {
+					load(service, location)
+				},
+			}, nil
+		})
+	}
+}
+
+// RegisterSource registers factory by name for LoadFrom(). It panics if
+// the name is already registered. The built-in sources are registered as
+// SourceEnv, SourceDotEnv, SourceArg, SourceJson, SourceYaml, and
+// SourceResource.
+func RegisterSource(name string, factory SourceFactory) {
+	sourceFactoriesMutex.Lock()
+	defer sourceFactoriesMutex.Unlock()
+
+	if _, ok := sourceFactories[name]; ok {
+		panic(fmt.Errorf("config: source '%s' is already registered", name))
+	}
+	sourceFactories[name] = factory
+}
+
+// unregisterSource removes the factory registered by name.
+func unregisterSource(name string) {
+	sourceFactoriesMutex.Lock()
+	defer sourceFactoriesMutex.Unlock()
+
+	delete(sourceFactories, name)
+}
+
+// LoadFrom creates the source registered by name from location, then
+// loads it like LoadSource().
+func (service *ConfigurationService) LoadFrom(name string, location string) *ConfigurationService {
+	sourceFactoriesMutex.RLock()
+	factory, ok := sourceFactories[name]
+	sourceFactoriesMutex.RUnlock()
+
+	var (
+		source Source
+		err    error
+	)
+	if !ok {
+		err = fmt.Errorf("config: unknown source '%s'", name)
+	} else {
+		source, err = factory(location)
+	}
+	if err != nil {
+		return service.load(&loadStep{
+			source: name,
+			apply: func(target interface{}, record common.Recorder) error {
+				return err
+			},
+		})
+	}
+	return service.LoadSource(source)
+}
+
+// LoadSource loads source like the built-in Load* methods. Its values
+// override the ones of the previous sources, and it takes part in the
+// provenance, the error collecting, and the reloading.
+func (service *ConfigurationService) LoadSource(source Source) *ConfigurationService {
+	if builtin, ok := source.(*builtinSource); ok {
+		builtin.load(service)
+		return service
+	}
+
+	name := source.Name()
+	step := &loadStep{
+		source: name,
+	}
+	switch s := source.(type) {
+	case TargetLoader:
+		step.apply = func(target interface{}, record common.Recorder) error {
+			return s.Load(target)
+		}
+	case KeyValueLoader:
+		step.tagName = name
+		step.naming = tagNaming(name)
+		step.apply = func(target interface{}, record common.Recorder) error {
+			values, err := s.LoadValues()
+			if err != nil {
+				return err
+			}
+			return keyvalue.Process(name, values, target, record)
+		}
+	default:
+		step.apply = func(target interface{}, record common.Recorder) error {
+			return fmt.Errorf("config: source '%s' must implement TargetLoader or KeyValueLoader", name)
+		}
+	}
+	if s, ok := source.(FileSource); ok {
+		for _, file := range s.Files() {
+			step.files = append(step.files, expandPath(file))
+		}
+		if len(step.files) == 1 {
+			step.location = step.files[0]
+		}
+	}
+	if s, ok := source.(WatchableSource); ok {
+		step.watch = s.Watch
+	}
+	return service.load(step)
+}
@@ -0,0 +1,85 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+const (
+	SourcesTagName = "sources"
+)
+
+var (
+	ErrSourceNotAllowed = errors.New("source not allowed")
+)
+
+// OnDisallowedSource registers fn to receive the assignments refused by
+// the sources tags, e.g. `sources:"env,resource"`, instead of failing the
+// loading. The refused values are discarded in both cases.
+func (service *ConfigurationService) OnDisallowedSource(fn func(err *FieldError)) *ConfigurationService {
+	service.disallowedSourceHandler = fn
+	return service
+}
+
+// restrictSources reverts the fields of current changed by step which
+// don't allow the source of step, and returns the refused assignments.
+func (service *ConfigurationService) restrictSources(step *loadStep, current reflect.Value) []*FieldError {
+	var errs []*FieldError
+	reflectutil.CompareLeaves(reflect.ValueOf(service.target), current,
+		func(path []reflect.StructField, x, y reflect.Value) {
+			allowed, ok := allowedSources(path, step.source)
+			if ok {
+				return
+			}
+
+			y.Set(reflectutil.DeepCopy(x))
+			err := &FieldError{
+				Source: step.source,
+				Field:  reflectutil.PathName(path),
+				Err:    fmt.Errorf("%w, expected from %s", ErrSourceNotAllowed, strings.Join(allowed, ", ")),
+			}
+			if step.naming != nil {
+				err.Key = step.naming(path)
+			}
+			errs = append(errs, err)
+		})
+	return errs
+}
+
+// allowedSources reports whether the field and all structs containing it
+// allow the source by their sources tags, and returns the allowed sources
+// of the tag which refuses it. The default values are always allowed.
+func allowedSources(path []reflect.StructField, source string) ([]string, bool) {
+	if source == SourceDefault {
+		return nil, true
+	}
+
+	for _, field := range path {
+		tag, ok := field.Tag.Lookup(SourcesTagName)
+		if !ok {
+			continue
+		}
+
+		allowed := strings.Split(tag, ",")
+		for i := range allowed {
+			allowed[i] = strings.TrimSpace(allowed[i])
+		}
+		if !contains(allowed, source) {
+			return allowed, false
+		}
+	}
+	return nil, true
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+type restrictedConfig struct {
+	RedisHost     string `env:"REDIS_HOST"       yaml:"redisHost"`
+	RedisPassword string `env:"REDIS_PASSWORD"   yaml:"redisPassword"   sources:"env,resource"`
+}
+
+func TestConfigurationService_WithDisallowedSource(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_PASSWORD", "1234")
+
+	conf := restrictedConfig{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadEnvironmentVariables("").
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisPassword: abcd")).
+		Err()
+
+	var expectedRedisHost = "127.0.0.1:6379"
+	if conf.RedisHost != expectedRedisHost {
+		t.Errorf("assert 'RedisHost':: expected '%v', got '%v'", expectedRedisHost, conf.RedisHost)
+	}
+	var expectedRedisPassword = "1234"
+	if conf.RedisPassword != expectedRedisPassword {
+		t.Errorf("assert 'RedisPassword':: expected '%v', got '%v'", expectedRedisPassword, conf.RedisPassword)
+	}
+
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 1 {
+		t.Fatalf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", 1, configurationError.Errors)
+	}
+	if !errors.Is(configurationError.Errors[0], ErrSourceNotAllowed) {
+		t.Errorf("assert 'ConfigurationError.Errors[0]':: expected '%v', got '%v'", ErrSourceNotAllowed, configurationError.Errors[0])
+	}
+	if !configurationError.Is(ErrSourceNotAllowed) || !errors.Is(err, ErrSourceNotAllowed) {
+		t.Errorf("assert 'ConfigurationError.Is()':: expected '%v', got '%v'", true, false)
+	}
+	var fieldError *FieldError
+	if !configurationError.As(&fieldError) || fieldError != configurationError.Errors[0] {
+		t.Errorf("assert 'ConfigurationError.As()':: expected '%v', got '%v'", configurationError.Errors[0], fieldError)
+	}
+	var expectedMessage = "yaml: field 'RedisPassword', key 'redisPassword': source not allowed, expected from env, resource"
+	if configurationError.Errors[0].Error() != expectedMessage {
+		t.Errorf("assert 'ConfigurationError.Errors[0].Error()':: expected '%v', got '%v'", expectedMessage, configurationError.Errors[0].Error())
+	}
+}
+
+func TestConfigurationService_OnDisallowedSource(t *testing.T) {
+	conf := restrictedConfig{}
+
+	var warnings []*FieldError
+	NewConfigurationService(&conf).
+		OnDisallowedSource(func(err *FieldError) {
+			warnings = append(warnings, err)
+		}).
+		LoadYamlBytes([]byte("redisHost: 127.0.0.1:6379\nredisPassword: abcd"))
+
+	if conf.RedisPassword != "" {
+		t.Errorf("assert 'RedisPassword':: expected '%v', got '%v'", "", conf.RedisPassword)
+	}
+	if len(warnings) != 1 || warnings[0].Field != "RedisPassword" {
+		t.Errorf("assert 'OnDisallowedSource()':: expected '%v', got '%v'", "RedisPassword", warnings)
+	}
+}
@@ -0,0 +1,154 @@
+package config
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+type mapSource struct {
+	values map[string]string
+	loaded int32
+}
+
+func (s *mapSource) Name() string { return "kv" }
+
+func (s *mapSource) LoadValues() (map[string]string, error) {
+	atomic.AddInt32(&s.loaded, 1)
+	return s.values, nil
+}
+
+type funcSource func(target interface{}) error
+
+func (s funcSource) Name() string { return "func" }
+
+func (s funcSource) Load(target interface{}) error { return s(target) }
+
+type notifySource struct {
+	funcSource
+	changed chan func()
+}
+
+func (s *notifySource) Watch(changed func()) (stop func()) {
+	s.changed <- changed
+	return func() {}
+}
+
+func TestConfigurationService_LoadSource(t *testing.T) {
+	conf := struct {
+		RedisHost string `kv:"REDIS_HOST"`
+		RedisDB   int    `kv:"REDIS_DB"     yaml:"redisDB"`
+		Workspace string `kv:"*WORKSPACE"   yaml:"-"`
+	}{}
+
+	source := &mapSource{
+		values: map[string]string{
+			"REDIS_HOST": "127.0.0.1:6379",
+			"REDIS_DB":   "3",
+		},
+	}
+	service := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadYamlBytes([]byte("redisDB: 1")).
+		LoadSource(source)
+
+	var expectedRedisHost = "127.0.0.1:6379"
+	if conf.RedisHost != expectedRedisHost {
+		t.Errorf("assert 'RedisHost':: expected '%v', got '%v'", expectedRedisHost, conf.RedisHost)
+	}
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+	var expectedOrigin = "kv REDIS_DB"
+	if origin := service.Origin("RedisDB"); origin == nil || origin.String() != expectedOrigin {
+		t.Errorf("assert 'ConfigurationService.Origin()':: expected '%v', got '%v'", expectedOrigin, origin)
+	}
+
+	err := service.Build()
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Build()':: expected '%T', got '%T'", configurationError, err)
+	}
+	var expectedMessage = "required: field 'Workspace': missing required value, expected from kv WORKSPACE"
+	if len(configurationError.Errors) != 1 || configurationError.Errors[0].Error() != expectedMessage {
+		t.Errorf("assert 'ConfigurationError.Errors':: expected '%v', got '%v'", expectedMessage, configurationError.Errors)
+	}
+
+	source.values["WORKSPACE"] = "demo"
+	service.Reload()
+	if n := atomic.LoadInt32(&source.loaded); n != 2 {
+		t.Errorf("assert 'mapSource.loaded':: expected '%v', got '%v'", 2, n)
+	}
+	if service.LastReloadFailure() != nil {
+		t.Errorf("assert 'ConfigurationService.LastReloadFailure()':: expected '%v', got '%v'", nil, service.LastReloadFailure())
+	}
+}
+
+func TestConfigurationService_LoadFrom(t *testing.T) {
+	RegisterSource("test-func", func(location string) (Source, error) {
+		return funcSource(func(target interface{}) error {
+			target.(*DummyConfig).Workspace = location
+			return nil
+		}), nil
+	})
+	t.Cleanup(func() {
+		unregisterSource("test-func")
+	})
+
+	conf := DummyConfig{}
+
+	err := NewConfigurationService(&conf).
+		CollectErrors().
+		LoadFrom(SourceYaml, "not-exist.yaml").
+		LoadFrom("test-func", "demo").
+		LoadFrom("unknown", "").
+		Err()
+
+	if conf.Workspace != "demo" {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", "demo", conf.Workspace)
+	}
+	configurationError, ok := err.(*ConfigurationError)
+	if !ok {
+		t.Fatalf("assert 'ConfigurationService.Err()':: expected '%T', got '%T'", configurationError, err)
+	}
+	if len(configurationError.Errors) != 1 || configurationError.Errors[0].Source != "unknown" {
+		t.Errorf("assert 'ConfigurationError.Errors':: expected the error of source '%v', got '%v'", "unknown", configurationError.Errors)
+	}
+}
+
+func TestConfigurationService_Watch_WithWatchableSource(t *testing.T) {
+	conf := DummyConfig{}
+
+	var workspace atomic.Value
+	workspace.Store("demo")
+	source := &notifySource{
+		funcSource: func(target interface{}) error {
+			target.(*DummyConfig).Workspace = workspace.Load().(string)
+			return nil
+		},
+		changed: make(chan func(), 1),
+	}
+
+	service := NewConfigurationService(&conf).
+		LoadSource(source)
+
+	reloaded := make(chan string, 1)
+	service.Subscribe(func(target interface{}) {
+		reloaded <- target.(*DummyConfig).Workspace
+	})
+
+	stop := service.Watch(time.Hour)
+	defer stop()
+
+	workspace.Store("demo_staging")
+	(<-source.changed)()
+
+	select {
+	case v := <-reloaded:
+		if v != "demo_staging" {
+			t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", "demo_staging", v)
+		}
+	case <-time.After(time.Second):
+		t.Errorf("assert 'ConfigurationService.Watch()':: expected reload")
+	}
+}
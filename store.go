@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+// Store holds the snapshot of a configuration for concurrent readers. The
+// snapshot is replaced as a whole when the ConfigurationService reloads,
+// so the readers always get a consistent value without locking.
+type Store[T any] struct {
+	value   atomic.Value
+	service *ConfigurationService
+}
+
+// NewStore creates a Store holding a copy of the loaded target of service,
+// and subscribes it to the reloads of service. The target of service must
+// be *T.
+func NewStore[T any](service *ConfigurationService) *Store[T] {
+	target, ok := service.target.(*T)
+	if !ok {
+		panic(fmt.Errorf("config: the target %T of ConfigurationService must be %T", service.target, target))
+	}
+
+	store := &Store[T]{
+		service: service,
+	}
+	store.Store(reflectutil.DeepCopy(reflect.ValueOf(target)).Interface().(*T))
+	service.Subscribe(func(target interface{}) {
+		store.Store(target.(*T))
+	})
+	return store
+}
+
+// Load returns the current snapshot. The snapshot is shared by all readers
+// and must not be modified.
+func (s *Store[T]) Load() *T {
+	return s.value.Load().(*T)
+}
+
+// Store publishes v as the current snapshot.
+func (s *Store[T]) Store(v *T) {
+	s.value.Store(v)
+}
+
+// Reload loads a fresh T by replaying the load chain of the service, and
+// publishes it if succeeded.
+func (s *Store[T]) Reload() error {
+	return s.service.Reload()
+}
@@ -0,0 +1,59 @@
+package config
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestStore(t *testing.T) {
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlBytes([]byte("redisDB: 3\nredisPoolSize: 10"))
+
+	store := NewStore[DummyConfig](service)
+	snapshot := store.Load()
+	if snapshot.RedisDB != 3 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 3, snapshot.RedisDB)
+	}
+	if snapshot == &conf {
+		t.Errorf("assert 'Store.Load()':: expected a copy of the target")
+	}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				c := store.Load()
+				if c.RedisPoolSize != 10 {
+					t.Errorf("assert 'DummyConfig.RedisPoolSize':: expected '%v', got '%v'", 10, c.RedisPoolSize)
+				}
+			}
+		}()
+	}
+	for i := 0; i < 10; i++ {
+		err := store.Reload()
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	wg.Wait()
+
+	if store.Load() == snapshot {
+		t.Errorf("assert 'Store.Load()':: expected a new snapshot after reload")
+	}
+}
+
+func TestNewStore_WithMismatchedType(t *testing.T) {
+	defer func() {
+		err := recover()
+		if err == nil {
+			t.Errorf("assert 'recover()':: expected error, got '%v'", err)
+		}
+	}()
+
+	conf := DummyConfig{}
+	NewStore[struct{}](NewConfigurationService(&conf))
+}
@@ -0,0 +1,236 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/Bofry/config/internal/reflectutil"
+)
+
+// A ReloadFailure records a rejected reload.
+type ReloadFailure struct {
+	Time time.Time
+	Err  error
+}
+
+type fileState struct {
+	exists  bool
+	size    int64
+	modTime time.Time
+}
+
+// Subscribe registers fn to receive the new target produced by Reload().
+// The target passed to fn is a fresh value of the same type as the one
+// given to NewConfigurationService; the original target is never changed.
+func (service *ConfigurationService) Subscribe(fn func(target interface{})) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.subscribers = append(service.subscribers, fn)
+}
+
+// OnReloadError registers fn to receive the errors of the rejected
+// reloads.
+func (service *ConfigurationService) OnReloadError(fn func(err error)) {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	service.reloadErrorHandlers = append(service.reloadErrorHandlers, fn)
+}
+
+// LastReloadFailure returns the last rejected reload, or nil if none.
+func (service *ConfigurationService) LastReloadFailure() *ReloadFailure {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+
+	return service.lastReloadFailure
+}
+
+// Reload replays the recorded load chain on a fresh copy of the initial
+// target, then calls Build() on it. If it succeeds, the subscribers receive
+// the new target, and the change handlers receive the differences from the
+// previous loaded one, except the changes of the fields tagged with
+// `reload:"static"`, which are reverted and passed to the restart required
+// handlers instead. If it fails, the reload is rejected, the last loaded
+// target stays in effect, and the error is passed to the reload error
+// handlers.
+func (service *ConfigurationService) Reload() error {
+	service.reloadMutex.Lock()
+	defer service.reloadMutex.Unlock()
+
+	target, err := service.replay()
+	if err != nil {
+		service.reject(err)
+		return err
+	}
+
+	if service.current == nil {
+		service.current = reflectutil.DeepCopy(reflect.ValueOf(service.target)).Interface()
+	}
+	changes, restartRequired := diff(service.current, target)
+	service.current = reflectutil.DeepCopy(reflect.ValueOf(target)).Interface()
+	restartRequired = service.unreported(restartRequired)
+
+	service.publish(target, changes, restartRequired)
+	return nil
+}
+
+// Watch polls the files of the loaded sources in the specified interval,
+// and calls Reload() when any of them changes, or when a WatchableSource
+// notifies its change. Call the returned function to stop watching.
+func (service *ConfigurationService) Watch(interval time.Duration) (stop func()) {
+	files := service.sourceFiles()
+	states := statFiles(files)
+
+	var stops []func()
+	for _, step := range service.steps {
+		if step.watch != nil {
+			stops = append(stops, step.watch(func() { service.Reload() }))
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+				current := statFiles(files)
+				if !reflect.DeepEqual(states, current) {
+					states = current
+					service.Reload()
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			close(done)
+			for _, stop := range stops {
+				stop()
+			}
+		})
+	}
+}
+
+func (service *ConfigurationService) replay() (target interface{}, err error) {
+	defer func() {
+		if ex := recover(); ex != nil {
+			target, err = nil, fmt.Errorf("config: reload failed: %v", ex)
+		}
+	}()
+
+	service.initialize()
+
+	replica := &ConfigurationService{
+		target:        reflectutil.DeepCopy(service.base).Interface(),
+		collectErrors: true,
+		origins:       make(map[string][]*Origin),
+
+		disallowedSourceHandler: service.disallowedSourceHandler,
+	}
+	for _, step := range service.steps {
+		replica.load(step)
+	}
+	for _, e := range service.expansions {
+		err = replica.expandEnv(e)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = replica.Build()
+	if err != nil {
+		return nil, err
+	}
+	return replica.target, nil
+}
+
+func (service *ConfigurationService) reject(err error) {
+	service.mutex.Lock()
+	service.lastReloadFailure = &ReloadFailure{
+		Time: time.Now(),
+		Err:  err,
+	}
+	handlers := append([]func(err error){}, service.reloadErrorHandlers...)
+	service.mutex.Unlock()
+
+	for _, fn := range handlers {
+		fn(err)
+	}
+}
+
+func (service *ConfigurationService) publish(target interface{}, changes, restartRequired []Change) {
+	service.mutex.Lock()
+	subscribers := append([]func(target interface{}){}, service.subscribers...)
+	changeHandlers := append([]*changeHandler{}, service.changeHandlers...)
+	restartHandlers := append([]func(changes []Change){}, service.restartHandlers...)
+	service.mutex.Unlock()
+
+	if len(restartRequired) > 0 {
+		for _, fn := range restartHandlers {
+			fn(restartRequired)
+		}
+	}
+	for _, fn := range subscribers {
+		fn(target)
+	}
+	for _, handler := range changeHandlers {
+		if matched := handler.match(changes); len(matched) > 0 {
+			handler.fn(matched)
+		}
+	}
+}
+
+func (service *ConfigurationService) sourceFiles() []string {
+	var (
+		files   []string
+		visited = make(map[string]bool)
+	)
+	for _, step := range service.steps {
+		for _, file := range step.files {
+			if !visited[file] {
+				visited[file] = true
+				files = append(files, file)
+			}
+		}
+	}
+	return files
+}
+
+func statFiles(files []string) map[string]fileState {
+	states := make(map[string]fileState, len(files))
+	for _, file := range files {
+		info, err := os.Stat(file)
+		if err != nil {
+			states[file] = fileState{}
+			continue
+		}
+		states[file] = fileState{
+			exists:  true,
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		}
+	}
+	return states
+}
+
+// collectKeys returns the keys of the fields of target named by naming.
+func collectKeys(target interface{}, naming keyNaming) []string {
+	var keys []string
+	reflectutil.WalkLeaves(reflect.ValueOf(target), func(path []reflect.StructField, rv reflect.Value) {
+		if key := naming(path); len(key) > 0 {
+			keys = append(keys, key)
+		}
+	})
+	return keys
+}
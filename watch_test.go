@@ -0,0 +1,233 @@
+package config
+
+import (
+	"os"
+	"path"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestConfigurationService_Watch(t *testing.T) {
+	dir := t.TempDir()
+	filename := path.Join(dir, "config.yaml")
+	err := os.WriteFile(filename, []byte("redisDB: 3\nworkspace: demo_test"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+
+	reloaded := make(chan *DummyConfig, 1)
+	service.Subscribe(func(target interface{}) {
+		reloaded <- target.(*DummyConfig)
+	})
+
+	stop := service.Watch(10 * time.Millisecond)
+	defer stop()
+
+	err = os.WriteFile(filename, []byte("redisDB: 12\nworkspace: demo_production"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case c := <-reloaded:
+		if c.RedisDB != 12 {
+			t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 12, c.RedisDB)
+		}
+		if c.Workspace != "demo_production" {
+			t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%v'", "demo_production", c.Workspace)
+		}
+		if c == &conf {
+			t.Errorf("assert 'DummyConfig':: expected a fresh value")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("timeout waiting for reload")
+	}
+
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+}
+
+func TestConfigurationService_Reload(t *testing.T) {
+	os.Clearenv()
+	t.Setenv("REDIS_HOST", "127.0.0.1:6379")
+	initializeArgs()
+
+	conf := DummyConfig{
+		RedisPoolSize: 10,
+	}
+
+	service := NewConfigurationService(&conf).
+		LoadEnvironmentVariables("").
+		LoadCommandArguments()
+
+	var reloaded *DummyConfig
+	service.Subscribe(func(target interface{}) {
+		reloaded = target.(*DummyConfig)
+	})
+
+	t.Setenv("REDIS_HOST", "127.0.0.3:6379")
+	err := service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := DummyConfig{
+		RedisHost:     "127.0.0.3:6379",
+		RedisDB:       32,
+		RedisPoolSize: 10,
+	}
+	if reloaded == nil || !reflect.DeepEqual(expected, *reloaded) {
+		t.Errorf("assert 'DummyConfig':: expected '%#+v', got '%#+v'", expected, reloaded)
+	}
+	if conf.RedisHost != "127.0.0.1:6379" {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", "127.0.0.1:6379", conf.RedisHost)
+	}
+}
+
+func TestConfigurationService_Reload_WithExpandEnv(t *testing.T) {
+	t.Setenv("Environment", "staging")
+
+	filename := path.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filename, []byte("redisDB: 3\nworkspace: demo_${Environment}"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+	err = service.ExpandEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		reloaded *DummyConfig
+		changes  []Change
+	)
+	service.Subscribe(func(target interface{}) {
+		reloaded = target.(*DummyConfig)
+	})
+	service.OnChange("", func(c []Change) {
+		changes = append(changes, c...)
+	})
+
+	err = service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reloaded == nil || reloaded.Workspace != "demo_staging" {
+		t.Errorf("assert 'DummyConfig.Workspace':: expected '%v', got '%#+v'", "demo_staging", reloaded)
+	}
+	if len(changes) != 0 {
+		t.Errorf("assert 'changes':: expected '%v', got '%v'", 0, changes)
+	}
+}
+
+func TestConfigurationService_Reload_WithInvalidFile(t *testing.T) {
+	filename := path.Join(t.TempDir(), "config.yaml")
+	err := os.WriteFile(filename, []byte("redisDB: 3"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := struct {
+		RedisDB int `yaml:"redisDB"   validate:"max=15"`
+	}{}
+
+	service := NewConfigurationService(&conf).
+		LoadYamlFile(filename)
+
+	var (
+		published int
+		rejected  []error
+	)
+	service.Subscribe(func(target interface{}) {
+		published++
+	})
+	service.OnReloadError(func(err error) {
+		rejected = append(rejected, err)
+	})
+
+	for _, content := range []string{"redisDB: [", "redisDB: 32"} {
+		err = os.WriteFile(filename, []byte(content), 0644)
+		if err != nil {
+			t.Fatal(err)
+		}
+		err = service.Reload()
+		if err == nil {
+			t.Errorf("assert 'ConfigurationService.Reload()':: expected error with '%s', got '%v'", content, err)
+		}
+	}
+
+	if published != 0 {
+		t.Errorf("assert 'published':: expected '%v', got '%v'", 0, published)
+	}
+	if len(rejected) != 2 {
+		t.Errorf("assert 'rejected':: expected '%v', got '%v'", 2, rejected)
+	}
+	failure := service.LastReloadFailure()
+	if failure == nil || failure.Err != rejected[len(rejected)-1] {
+		t.Errorf("assert 'ConfigurationService.LastReloadFailure()':: expected '%v', got '%#+v'", rejected[len(rejected)-1], failure)
+	}
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+}
+
+func TestConfigurationService_Reload_WithDotEnvFiles(t *testing.T) {
+	t.Setenv("REDIS_HOST", "")
+	os.Unsetenv("REDIS_HOST")
+	t.Setenv("REDIS_DB", "")
+	os.Unsetenv("REDIS_DB")
+
+	dir := t.TempDir()
+	first, second := path.Join(dir, "a.env"), path.Join(dir, "b.env")
+	err := os.WriteFile(first, []byte("REDIS_HOST=192.168.56.53:6379"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = os.WriteFile(second, []byte("REDIS_HOST=192.168.56.54:6379\nREDIS_DB=3"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	conf := DummyConfig{}
+
+	service := NewConfigurationService(&conf).
+		LoadDotEnvFile(first).
+		LoadDotEnvFile(second)
+
+	if conf.RedisHost != "192.168.56.53:6379" {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", "192.168.56.53:6379", conf.RedisHost)
+	}
+	if conf.RedisDB != 3 {
+		t.Errorf("assert 'DummyConfig.RedisDB':: expected '%v', got '%v'", 3, conf.RedisDB)
+	}
+
+	var reloaded *DummyConfig
+	service.Subscribe(func(target interface{}) {
+		reloaded = target.(*DummyConfig)
+	})
+
+	err = os.WriteFile(first, []byte("REDIS_HOST=192.168.56.55:6379"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = service.Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if reloaded == nil || reloaded.RedisHost != "192.168.56.55:6379" {
+		t.Errorf("assert 'DummyConfig.RedisHost':: expected '%v', got '%v'", "192.168.56.55:6379", reloaded)
+	}
+}
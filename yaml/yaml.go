@@ -3,9 +3,9 @@ package yaml
 import "github.com/Bofry/config/internal/yaml"
 
 func LoadFile(filepath string, target interface{}) error {
-	return yaml.LoadFile(filepath, target)
+	return yaml.LoadFile(filepath, target, nil)
 }
 
 func LoadBytes(buffer []byte, target interface{}) error {
-	return yaml.LoadBytes(buffer, target)
+	return yaml.LoadBytes(buffer, target, nil)
 }
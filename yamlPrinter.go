@@ -0,0 +1,81 @@
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+var _ Printer = new(YamlPrinter)
+
+// YamlPrinter prints the target in YAML by its yaml tags. The fields are
+// printed in declaration order, and the secret fields are masked.
+type YamlPrinter struct {
+	writer io.Writer
+	prefix string
+	indent int
+}
+
+func NewYamlPrinter(writer io.Writer) *YamlPrinter {
+	return &YamlPrinter{
+		writer: writer,
+		indent: 2,
+	}
+}
+
+// SetPrefix makes the printer begin each line with prefix, e.g. to embed
+// the output in another YAML document.
+func (p *YamlPrinter) SetPrefix(prefix string) *YamlPrinter {
+	p.prefix = prefix
+	return p
+}
+
+// SetIndent sets the number of spaces which indent the nested levels, 2
+// by default. Print() fails if spaces is out of the range from 2 to 9
+// supported by YAML emitters.
+func (p *YamlPrinter) SetIndent(spaces int) *YamlPrinter {
+	p.indent = spaces
+	return p
+}
+
+func (p *YamlPrinter) Print(target interface{}) error {
+	if p.indent < 2 || p.indent > 9 {
+		return fmt.Errorf("config: invalid yaml indent %d, expected from 2 to 9", p.indent)
+	}
+
+	var buffer bytes.Buffer
+	encoder := yaml.NewEncoder(&buffer)
+	encoder.SetIndent(p.indent)
+	err := encoder.Encode(MaskSecrets(target))
+	if err != nil {
+		return err
+	}
+	err = encoder.Close()
+	if err != nil {
+		return err
+	}
+	if len(p.prefix) == 0 {
+		_, err = p.writer.Write(buffer.Bytes())
+		return err
+	}
+
+	var out bytes.Buffer
+	scanner := bufio.NewScanner(&buffer)
+	// NOTE: a line is never longer than the whole output, e.g. a long
+	// string value, so it won't exceed the limit of the scanner
+	scanner.Buffer(nil, buffer.Len()+1)
+	for scanner.Scan() {
+		out.WriteString(p.prefix)
+		out.Write(scanner.Bytes())
+		out.WriteByte('\n')
+	}
+	err = scanner.Err()
+	if err != nil {
+		return err
+	}
+	_, err = p.writer.Write(out.Bytes())
+	return err
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+type yamlPrinterConfig struct {
+	RedisHost string `yaml:"redisHost"`
+	Message   string `yaml:"message"`
+}
+
+func TestYamlPrinter_WithPrefixAndLongLine(t *testing.T) {
+	conf := yamlPrinterConfig{
+		RedisHost: "127.0.0.1:6379",
+		Message:   strings.Repeat("x", 100*1024),
+	}
+
+	var buffer bytes.Buffer
+	err := NewYamlPrinter(&buffer).
+		SetPrefix("  ").
+		Print(&conf)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var expected = "  redisHost: 127.0.0.1:6379\n  message: " + conf.Message + "\n"
+	if buffer.String() != expected {
+		t.Errorf("assert 'YamlPrinter.Print()':: expected '%v' bytes, got '%v' bytes", len(expected), buffer.Len())
+	}
+}
+
+func TestYamlPrinter_WithInvalidIndent(t *testing.T) {
+	conf := yamlPrinterConfig{
+		RedisHost: "127.0.0.1:6379",
+	}
+
+	for _, spaces := range []int{-1, 0, 10} {
+		var buffer bytes.Buffer
+		err := NewYamlPrinter(&buffer).
+			SetIndent(spaces).
+			Print(&conf)
+		if err == nil {
+			t.Errorf("assert 'YamlPrinter.Print()':: expected error with indent '%v', got '%v'", spaces, err)
+		}
+		if buffer.Len() != 0 {
+			t.Errorf("assert 'YamlPrinter.Print()':: expected '%v', got '%v'", "", buffer.String())
+		}
+	}
+}